@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+// arcCache implements the Adaptive Replacement Cache policy. Like 2Q it keeps the recent (T1)
+// and frequent (T2) queues with the ghost lists of evicted keys (B1, B2), but the target size
+// of the recent queue is adjusted on every ghost hit.
+type arcCache[K comparable, V any] struct {
+	queues[K, V]
+	target int
+
+	recentGhost   *ghostList[K]
+	frequentGhost *ghostList[K]
+}
+
+func (c *arcCache[K, V]) Set(key K, value V) bool {
+	return c.SetWithTTL(key, value, 0)
+}
+
+func (c *arcCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e := entry[K, V]{key: key, value: value, expiresAt: expiration(ttl)}
+	if c.update(e) {
+		return true
+	}
+
+	switch {
+	case c.recentGhost.Remove(key):
+		c.target = min(c.target+max(c.frequentGhost.Len()/(c.recentGhost.Len()+1), 1), c.capacity)
+		c.replace(false)
+	case c.frequentGhost.Remove(key):
+		c.target = max(c.target-max(c.recentGhost.Len()/(c.frequentGhost.Len()+1), 1), 0)
+		c.replace(true)
+	default:
+		c.replace(false)
+		if c.recentGhost.Len() > c.capacity-c.target {
+			c.recentGhost.RemoveOldest()
+		}
+		if c.frequentGhost.Len() > c.target {
+			c.frequentGhost.RemoveOldest()
+		}
+		c.push(e, false)
+		return false
+	}
+
+	c.push(e, true)
+	return false
+}
+
+// replace frees a slot for a new entry moving the evicted key to the matching ghost list.
+func (c *arcCache[K, V]) replace(frequentGhostHit bool) {
+	if !c.full() {
+		return
+	}
+
+	recentLen := c.recent.Len()
+	if recentLen > 0 && (recentLen > c.target || (recentLen == c.target && frequentGhostHit) ||
+		c.frequent.Len() == 0) {
+		el := c.recent.Back()
+		c.remove(el, EvictedCapacity)
+		c.recentGhost.Push(el.Value.key)
+		return
+	}
+
+	el := c.frequent.Back()
+	c.remove(el, EvictedCapacity)
+	c.frequentGhost.Push(el.Value.key)
+}
+
+func (c *arcCache[K, V]) resetGhosts() {
+	c.target = 0
+	c.recentGhost = newGhostList[K]()
+	c.frequentGhost = newGhostList[K]()
+}
+
+func NewARCCache(capacity int, opts ...Option[list.Key, interface{}]) Cache {
+	return NewTypedARCCache[list.Key, interface{}](capacity, opts...)
+}
+
+func NewTypedARCCache[K comparable, V any](capacity int, opts ...Option[K, V]) TypedCache[K, V] {
+	o := newOptions(opts)
+	c := &arcCache[K, V]{}
+	c.init(capacity, o)
+	c.resetPolicy = c.resetGhosts
+	c.reset()
+	c.startJanitor(o, c.removeExpired)
+	return c
+}
@@ -1,72 +1,232 @@
 package cache
 
 import (
-	"sync"
+	"fmt"
+	"time"
 
 	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
 )
 
-type Cache interface {
-	Set(key list.Key, value interface{}) bool
-	Get(key list.Key) (interface{}, bool)
+type TypedCache[K comparable, V any] interface {
+	Set(key K, value V) bool
+	SetWithTTL(key K, value V, ttl time.Duration) bool
+	Get(key K) (V, bool)
+	Delete(key K) bool
 	Clear()
+	Close()
+	Stats() Stats
 }
 
-type lruCache struct {
-	capacity int
-	queue    list.List
-	items    map[list.Key]*list.Item
-	m        sync.Mutex
+type Cache = TypedCache[list.Key, interface{}]
+
+type lruCache[K comparable, V any] struct {
+	core[K, V]
+	queue list.TypedList[entry[K, V]]
+	items map[K]*list.TypedItem[entry[K, V]]
+
+	// maxCost enables the cost mode in which the entries are limited by their total cost
+	// instead of their number.
+	maxCost int64
+	cost    int64
+	sizer   Sizer[K, V]
+
+	codec Codec
+}
+
+func (c *lruCache[K, V]) Set(key K, value V) bool {
+	return c.SetWithTTL(key, value, 0)
+}
+
+// SetWithTTL stores the value which expires after ttl. Non-positive ttl means no expiration.
+func (c *lruCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	wasInCache, _ := c.set(key, value, c.costOf(key, value), expiration(ttl))
+	return wasInCache
+}
+
+// TrySet works like Set, but reports the error if the cost of the value is rejected.
+func (c *lruCache[K, V]) TrySet(key K, value V) (bool, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.set(key, value, c.costOf(key, value), time.Time{})
 }
 
-func (c *lruCache) Set(key list.Key, value interface{}) bool {
+func (c *lruCache[K, V]) SetWithCost(key K, value V, cost int64) (bool, error) {
 	c.m.Lock()
 	defer c.m.Unlock()
-	_, ok := c.items[key]
 
-	if ok {
-		newElem := &list.Item{Value: value}
-		c.items[key] = newElem
-		c.queue.MoveToFront(newElem)
-		return ok
+	return c.set(key, value, cost, time.Time{})
+}
+
+// set stores the entry. If the cost is rejected, the previous entry of the key is evicted,
+// so the stale value is not returned after the failed overwrite.
+func (c *lruCache[K, V]) set(key K, value V, cost int64, expiresAt time.Time) (bool, error) {
+	if err := c.checkCost(cost); err != nil {
+		el, ok := c.items[key]
+		if !ok {
+			return false, err
+		}
+		if el.Value.expired(time.Now()) {
+			c.remove(el, EvictedExpired)
+			return false, err
+		}
+		c.remove(el, EvictedCapacity)
+		return true, err
+	}
+
+	if el, ok := c.items[key]; ok {
+		if !el.Value.expired(time.Now()) {
+			c.cost += cost - el.Value.cost
+			el.Value.value = value
+			el.Value.expiresAt = expiresAt
+			el.Value.cost = cost
+			c.queue.MoveToFront(el)
+			c.fitBudget(0, 1)
+			return ok, nil
+		}
+		c.remove(el, EvictedExpired)
+	}
+
+	if c.maxCost > 0 {
+		c.fitBudget(cost, 0)
+	} else if c.queue.Len() >= c.capacity && c.queue.Len() > 0 {
+		c.remove(c.queue.Back(), EvictedCapacity)
 	}
 
-	if c.queue.Len() == c.capacity {
-		delete(c.items, c.queue.Back().Key)
-		c.queue.Remove(c.queue.Back())
+	c.items[key] = c.queue.PushFront(entry[K, V]{key: key, value: value, expiresAt: expiresAt, cost: cost})
+	c.cost += cost
+
+	return false, nil
+}
+
+// fitBudget evicts the least recently used entries until the extra cost fits into the budget.
+// The keep most recently used entries are never evicted.
+func (c *lruCache[K, V]) fitBudget(cost int64, keep int) {
+	for c.maxCost > 0 && c.cost+cost > c.maxCost && c.queue.Len() > keep {
+		c.remove(c.queue.Back(), EvictedCapacity)
 	}
+}
 
-	newElem := c.queue.PushFront(value)
-	newElem.Key = key
-	c.items[key] = newElem
+func (c *lruCache[K, V]) checkCost(cost int64) error {
+	switch {
+	case c.maxCost <= 0:
+		return nil
+	case cost < 0:
+		return fmt.Errorf("%w: %d", ErrInvalidCost, cost)
+	case cost > c.maxCost:
+		return fmt.Errorf("%w: cost %d, budget %d", ErrCostExceedsBudget, cost, c.maxCost)
+	}
+	return nil
+}
 
-	return ok
+func (c *lruCache[K, V]) costOf(key K, value V) int64 {
+	if c.maxCost <= 0 {
+		return 0
+	}
+	if c.sizer == nil {
+		return 1
+	}
+	return c.sizer(key, value)
 }
 
-func (c *lruCache) Get(key list.Key) (interface{}, bool) {
+func (c *lruCache[K, V]) Get(key K) (V, bool) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
 	if el, ok := c.items[key]; ok {
-		c.queue.MoveToFront(el)
-		return el.Value, ok
+		if !el.Value.expired(time.Now()) {
+			c.hits++
+			c.queue.MoveToFront(el)
+			return el.Value.value, ok
+		}
+		c.remove(el, EvictedExpired)
+	}
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+func (c *lruCache[K, V]) Delete(key K) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if el.Value.expired(time.Now()) {
+		c.remove(el, EvictedExpired)
+		return false
+	}
+	c.remove(el, EvictedDeleted)
+	return true
+}
+
+func (c *lruCache[K, V]) Clear() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.onEvict != nil {
+		for el := c.queue.Back(); el != nil; el = el.Prev {
+			c.onEvict(el.Value.key, el.Value.value, EvictedCleared)
+		}
+	}
+	c.queue = list.NewTypedList[entry[K, V]]()
+	c.items = map[K]*list.TypedItem[entry[K, V]]{}
+	c.cost = 0
+}
+
+func (c *lruCache[K, V]) Stats() Stats {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	stats := c.stats(c.queue.Len())
+	if c.maxCost > 0 {
+		stats.Cost = c.cost
+		stats.MaxCost = c.maxCost
 	}
-	return nil, false
+	return stats
 }
 
-func (c *lruCache) Clear() {
+func (c *lruCache[K, V]) removeExpired() {
 	c.m.Lock()
 	defer c.m.Unlock()
 
-	c.queue = list.NewList()
-	c.items = map[list.Key]*list.Item{}
+	now := time.Now()
+	for el := range c.queue.Backward() {
+		if el.Value.expired(now) {
+			c.remove(el, EvictedExpired)
+		}
+	}
+}
+
+func (c *lruCache[K, V]) remove(el *list.TypedItem[entry[K, V]], reason EvictReason) {
+	delete(c.items, el.Value.key)
+	c.queue.Remove(el)
+	c.cost -= el.Value.cost
+	c.evicted(&el.Value, reason)
+}
+
+func NewCache(capacity int, opts ...Option[list.Key, interface{}]) Cache {
+	return NewTypedCache[list.Key, interface{}](capacity, opts...)
+}
+
+func NewTypedCache[K comparable, V any](capacity int, opts ...Option[K, V]) TypedCache[K, V] {
+	o := newOptions(opts)
+	c := newLRUCache(capacity, o)
+	c.startJanitor(o, c.removeExpired)
+	return c
 }
 
-func NewCache(capacity int) Cache {
-	return &lruCache{
-		capacity: capacity,
-		queue:    list.NewList(),
-		items:    make(map[list.Key]*list.Item, capacity),
-		m:        sync.Mutex{},
+func newLRUCache[K comparable, V any](capacity int, o options[K, V]) *lruCache[K, V] {
+	c := &lruCache[K, V]{
+		queue: list.NewTypedList[entry[K, V]](),
+		items: make(map[K]*list.TypedItem[entry[K, V]], capacity),
+		codec: o.codec,
 	}
+	c.init(capacity, o)
+	return c
 }
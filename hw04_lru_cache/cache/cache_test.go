@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -96,6 +97,191 @@ func TestCache(t *testing.T) {
 	})
 }
 
+func TestTypedCache(t *testing.T) {
+	t.Run("struct keys", func(t *testing.T) {
+		type point struct{ x, y int }
+		c := cache.NewTypedCache[point, string](2)
+
+		wasInCache := c.Set(point{1, 2}, "aaa")
+		require.False(t, wasInCache)
+		wasInCache = c.Set(point{2, 1}, "bbb")
+		require.False(t, wasInCache)
+
+		val, ok := c.Get(point{1, 2})
+		require.True(t, ok)
+		require.Equal(t, "aaa", val)
+
+		wasInCache = c.Set(point{3, 3}, "ccc")
+		require.False(t, wasInCache)
+
+		val, ok = c.Get(point{2, 1})
+		require.False(t, ok)
+		require.Equal(t, "", val)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		c := cache.NewTypedCache[int, int](3)
+
+		c.Set(1, 100)
+		c.Set(2, 200)
+		c.Clear()
+
+		_, ok := c.Get(1)
+		require.False(t, ok)
+		_, ok = c.Get(2)
+		require.False(t, ok)
+
+		wasInCache := c.Set(1, 300)
+		require.False(t, wasInCache)
+	})
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Run("lazy expiry", func(t *testing.T) {
+		c := cache.NewCache(5)
+		defer c.Close()
+
+		wasInCache := c.SetWithTTL("aaa", 100, 50*time.Millisecond)
+		require.False(t, wasInCache)
+		wasInCache = c.Set("bbb", 200)
+		require.False(t, wasInCache)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+
+		time.Sleep(100 * time.Millisecond)
+
+		val, ok = c.Get("aaa")
+		require.False(t, ok)
+		require.Nil(t, val)
+
+		val, ok = c.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("expired entry is not in cache", func(t *testing.T) {
+		c := cache.NewCache(5)
+		defer c.Close()
+
+		c.SetWithTTL("aaa", 100, 10*time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+
+		wasInCache := c.Set("aaa", 200)
+		require.False(t, wasInCache)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("set resets ttl", func(t *testing.T) {
+		c := cache.NewCache(5)
+		defer c.Close()
+
+		c.SetWithTTL("aaa", 100, 20*time.Millisecond)
+		wasInCache := c.Set("aaa", 200)
+		require.True(t, wasInCache)
+		time.Sleep(40 * time.Millisecond)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		c := cache.NewCache(2, cache.WithJanitor[list.Key, interface{}](10*time.Millisecond))
+		defer c.Close()
+
+		c.Set("bbb", 200)
+		c.SetWithTTL("aaa", 100, 20*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+
+		// "aaa" has been swept, so "bbb" is not pushed out by "ccc".
+		c.Set("ccc", 300)
+
+		val, ok := c.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("close is idempotent", func(t *testing.T) {
+		c := cache.NewTypedCache(1, cache.WithJanitor[string, int](time.Millisecond))
+
+		c.Close()
+		c.Close()
+	})
+}
+
+func TestCacheOnEvict(t *testing.T) {
+	type evicted struct {
+		key    list.Key
+		value  interface{}
+		reason cache.EvictReason
+	}
+	var got []evicted
+	c := cache.NewCache(2, cache.WithOnEvict(func(key list.Key, value interface{}, reason cache.EvictReason) {
+		got = append(got, evicted{key, value, reason})
+	}))
+	defer c.Close()
+
+	c.Set("aaa", 100)
+	c.Set("bbb", 200)
+	c.Set("ccc", 300) // [ccc, bbb]
+	require.Equal(t, []evicted{{"aaa", 100, cache.EvictedCapacity}}, got)
+
+	got = nil
+	c.SetWithTTL("ddd", 400, 10*time.Millisecond) // [ddd, ccc]
+	time.Sleep(20 * time.Millisecond)
+	_, ok := c.Get("ddd") // [ccc]
+	require.False(t, ok)
+	require.Equal(t, []evicted{
+		{"bbb", 200, cache.EvictedCapacity},
+		{"ddd", 400, cache.EvictedExpired},
+	}, got)
+
+	got = nil
+	c.Set("eee", 500) // [eee, ccc]
+	require.True(t, c.Delete("ccc"))
+	require.False(t, c.Delete("ccc"))
+	require.Equal(t, []evicted{{"ccc", 300, cache.EvictedDeleted}}, got)
+
+	got = nil
+	c.Set("fff", 600) // [fff, eee]
+	c.Clear()
+	require.Equal(t, []evicted{
+		{"eee", 500, cache.EvictedCleared},
+		{"fff", 600, cache.EvictedCleared},
+	}, got)
+}
+
+func TestCacheStats(t *testing.T) {
+	c := cache.NewTypedCache[string, int](2)
+	defer c.Close()
+
+	require.Equal(t, cache.Stats{Capacity: 2}, c.Stats())
+
+	c.Set("aaa", 100)
+	c.Set("bbb", 200)
+	c.Get("aaa")
+	c.Get("ccc")
+	c.Set("ccc", 300) // evicts "bbb"
+	c.Get("bbb")
+	c.SetWithTTL("ddd", 400, time.Nanosecond) // evicts "aaa"
+	time.Sleep(time.Millisecond)
+	c.Get("ddd")
+	c.Delete("ccc")
+
+	require.Equal(t, cache.Stats{
+		Hits:      1,
+		Misses:    3,
+		Evictions: 3,
+		Size:      0,
+		Capacity:  2,
+	}, c.Stats())
+}
+
 func TestCacheMultithreading(_ *testing.T) {
 	c := cache.NewCache(10)
 	wg := &sync.WaitGroup{}
@@ -117,3 +303,28 @@ func TestCacheMultithreading(_ *testing.T) {
 
 	wg.Wait()
 }
+
+func BenchmarkCache(b *testing.B) {
+	for _, size := range []int{1_000, 1_000_000} {
+		c := cache.NewCache(size)
+		for i := 0; i < size; i++ {
+			c.Set(list.Key(strconv.Itoa(i)), i)
+		}
+		keys := make([]list.Key, size)
+		for i := range keys {
+			keys[i] = list.Key(strconv.Itoa(i))
+		}
+
+		b.Run("Get/"+strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.Get(keys[i%size])
+			}
+		})
+
+		b.Run("Set/"+strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.Set(keys[i%size], i)
+			}
+		})
+	}
+}
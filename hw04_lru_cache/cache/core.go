@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+// core holds the state shared by all eviction policies: the lock, the hook and the counters.
+// evicted and stats expect the lock to be held.
+type core[K comparable, V any] struct {
+	capacity int
+	m        sync.Mutex
+	janitor  *janitor
+	onEvict  func(key K, value V, reason EvictReason)
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func (c *core[K, V]) init(capacity int, o options[K, V]) {
+	c.capacity = capacity
+	c.onEvict = o.onEvict
+}
+
+func (c *core[K, V]) startJanitor(o options[K, V], sweep func()) {
+	if o.janitorInterval > 0 {
+		c.janitor = startJanitor(o.janitorInterval, sweep)
+	}
+}
+
+// Close stops the janitor goroutine if it was started.
+func (c *core[K, V]) Close() {
+	c.janitor.Stop()
+}
+
+func (c *core[K, V]) evicted(e *entry[K, V], reason EvictReason) {
+	if reason == EvictedCapacity || reason == EvictedExpired {
+		c.evictions++
+	}
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value, reason)
+	}
+}
+
+func (c *core[K, V]) stats(size int) Stats {
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      size,
+		Capacity:  c.capacity,
+	}
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	cost      int64
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// queuedEntry is an entry of the policies splitting the cache into the recent and frequent queues.
+type queuedEntry[K comparable, V any] struct {
+	entry[K, V]
+	frequent bool
+}
+
+// queues holds the recent and frequent queues shared by 2Q and ARC. The policies decide
+// which entry to evict and keep the ghost lists, resetPolicy resets them on Clear.
+// Unexported methods expect the lock to be held.
+type queues[K comparable, V any] struct {
+	core[K, V]
+	resetPolicy func()
+
+	recent   list.TypedList[queuedEntry[K, V]]
+	frequent list.TypedList[queuedEntry[K, V]]
+	items    map[K]*list.TypedItem[queuedEntry[K, V]]
+}
+
+// Get promotes the entry from the recent queue to the frequent one on the second access.
+func (c *queues[K, V]) Get(key K) (V, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		if !el.Value.expired(time.Now()) {
+			c.hits++
+			if el.Value.frequent {
+				c.frequent.MoveToFront(el)
+			} else {
+				c.recent.Remove(el)
+				c.push(el.Value.entry, true)
+			}
+			return el.Value.value, ok
+		}
+		c.remove(el, EvictedExpired)
+	}
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+func (c *queues[K, V]) Delete(key K) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if el.Value.expired(time.Now()) {
+		c.remove(el, EvictedExpired)
+		return false
+	}
+	c.remove(el, EvictedDeleted)
+	return true
+}
+
+func (c *queues[K, V]) Clear() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.onEvict != nil {
+		for _, q := range []list.TypedList[queuedEntry[K, V]]{c.recent, c.frequent} {
+			for el := q.Back(); el != nil; el = el.Prev {
+				c.onEvict(el.Value.key, el.Value.value, EvictedCleared)
+			}
+		}
+	}
+	c.reset()
+}
+
+func (c *queues[K, V]) Stats() Stats {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.stats(len(c.items))
+}
+
+func (c *queues[K, V]) removeExpired() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := time.Now()
+	for _, el := range c.items {
+		if el.Value.expired(now) {
+			c.remove(el, EvictedExpired)
+		}
+	}
+}
+
+// update replaces the value of the existing key and moves it to the frequent queue.
+// It reports false if the key is missing or expired, the expired entry is removed.
+func (c *queues[K, V]) update(e entry[K, V]) bool {
+	el, ok := c.items[e.key]
+	if !ok {
+		return false
+	}
+	if el.Value.expired(time.Now()) {
+		c.remove(el, EvictedExpired)
+		return false
+	}
+	c.queueOf(el).Remove(el)
+	c.push(e, true)
+	return true
+}
+
+func (c *queues[K, V]) push(e entry[K, V], frequent bool) {
+	q := c.recent
+	if frequent {
+		q = c.frequent
+	}
+	c.items[e.key] = q.PushFront(queuedEntry[K, V]{entry: e, frequent: frequent})
+}
+
+// full reports if an entry must be evicted before adding a new one.
+func (c *queues[K, V]) full() bool {
+	return c.recent.Len()+c.frequent.Len() >= c.capacity && len(c.items) > 0
+}
+
+func (c *queues[K, V]) remove(el *list.TypedItem[queuedEntry[K, V]], reason EvictReason) {
+	c.queueOf(el).Remove(el)
+	delete(c.items, el.Value.key)
+	c.evicted(&el.Value.entry, reason)
+}
+
+func (c *queues[K, V]) queueOf(el *list.TypedItem[queuedEntry[K, V]]) list.TypedList[queuedEntry[K, V]] {
+	if el.Value.frequent {
+		return c.frequent
+	}
+	return c.recent
+}
+
+func (c *queues[K, V]) reset() {
+	c.recent = list.NewTypedList[queuedEntry[K, V]]()
+	c.frequent = list.NewTypedList[queuedEntry[K, V]]()
+	c.items = make(map[K]*list.TypedItem[queuedEntry[K, V]], c.capacity)
+	c.resetPolicy()
+}
+
+func expiration(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"errors"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+var (
+	ErrCostExceedsBudget = errors.New("cost exceeds cache budget")
+	ErrInvalidCost       = errors.New("invalid cost")
+)
+
+// Sizer computes the cost of the entry, e.g. the size of the value in bytes.
+type Sizer[K comparable, V any] func(key K, value V) int64
+
+// CostCache limits the total cost of the entries instead of their number.
+// Set, SetWithTTL and TrySet take the cost from the Sizer or count every entry as 1 without it.
+// Set and SetWithTTL silently skip the values exceeding the budget, while TrySet and SetWithCost
+// return ErrCostExceedsBudget for them. In all cases the previous value of the key is
+// evicted with EvictedCapacity and wasInCache reports if it was there.
+type CostCache[K comparable, V any] interface {
+	TypedCache[K, V]
+	TrySet(key K, value V) (bool, error)
+	SetWithCost(key K, value V, cost int64) (bool, error)
+}
+
+func NewCostCache(maxCost int64, opts ...Option[list.Key, interface{}]) CostCache[list.Key, interface{}] {
+	return NewTypedCostCache[list.Key, interface{}](maxCost, opts...)
+}
+
+// NewTypedCostCache creates an LRU cache evicting the least recently used entries until
+// the total cost fits into maxCost. maxCost must be positive.
+func NewTypedCostCache[K comparable, V any](maxCost int64, opts ...Option[K, V]) CostCache[K, V] {
+	o := newOptions(opts)
+	c := newLRUCache(0, o)
+	c.maxCost = maxCost
+	c.sizer = o.sizer
+	c.startJanitor(o, c.removeExpired)
+	return c
+}
@@ -0,0 +1,167 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/cache"
+)
+
+func TestCostCache(t *testing.T) {
+	t.Run("evicts until cost fits", func(t *testing.T) {
+		c := cache.NewTypedCostCache[string, string](10)
+		defer c.Close()
+
+		for _, key := range []string{"aaa", "bbb", "ccc"} {
+			wasInCache, err := c.SetWithCost(key, key, 3)
+			require.NoError(t, err)
+			require.False(t, wasInCache)
+		}
+		c.Get("aaa") // [aaa, ccc, bbb]
+
+		wasInCache, err := c.SetWithCost("ddd", "ddd", 5) // [ddd, aaa]
+		require.NoError(t, err)
+		require.False(t, wasInCache)
+
+		for key, expected := range map[string]bool{"aaa": true, "bbb": false, "ccc": false, "ddd": true} {
+			_, ok := c.Get(key)
+			require.Equal(t, expected, ok, key)
+		}
+
+		stats := c.Stats()
+		require.Equal(t, 2, stats.Size)
+		require.Equal(t, int64(8), stats.Cost)
+		require.Equal(t, int64(10), stats.MaxCost)
+	})
+
+	t.Run("update changes cost", func(t *testing.T) {
+		c := cache.NewTypedCostCache[string, string](10)
+		defer c.Close()
+
+		c.SetWithCost("aaa", "aaa", 3)
+		c.SetWithCost("bbb", "bbb", 3)
+
+		wasInCache, err := c.SetWithCost("aaa", "aaaaaaaa", 8) // [aaa]
+		require.NoError(t, err)
+		require.True(t, wasInCache)
+
+		_, ok := c.Get("bbb")
+		require.False(t, ok)
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, "aaaaaaaa", val)
+		require.Equal(t, int64(8), c.Stats().Cost)
+
+		c.SetWithCost("aaa", "a", 1)
+		require.Equal(t, int64(1), c.Stats().Cost)
+	})
+
+	t.Run("value larger than budget", func(t *testing.T) {
+		c := cache.NewTypedCostCache[string, string](10)
+		defer c.Close()
+
+		c.SetWithCost("aaa", "aaa", 3)
+
+		wasInCache, err := c.SetWithCost("bbb", "bbb", 11)
+		require.Truef(t, errors.Is(err, cache.ErrCostExceedsBudget), "actual err - %v", err)
+		require.False(t, wasInCache)
+
+		_, err = c.SetWithCost("bbb", "bbb", -1)
+		require.Truef(t, errors.Is(err, cache.ErrInvalidCost), "actual err - %v", err)
+
+		_, ok := c.Get("aaa")
+		require.True(t, ok)
+		_, ok = c.Get("bbb")
+		require.False(t, ok)
+		require.Equal(t, int64(3), c.Stats().Cost)
+	})
+
+	t.Run("sizer", func(t *testing.T) {
+		c := cache.NewTypedCostCache(10, cache.WithSizer(func(_ string, value []byte) int64 {
+			return int64(len(value))
+		}))
+		defer c.Close()
+
+		c.Set("aaa", make([]byte, 4))
+		c.Set("bbb", make([]byte, 4))
+		c.Set("ccc", make([]byte, 4))
+		c.Set("ddd", make([]byte, 20))
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		_, ok = c.Get("ddd")
+		require.False(t, ok)
+
+		stats := c.Stats()
+		require.Equal(t, 2, stats.Size)
+		require.Equal(t, int64(8), stats.Cost)
+	})
+
+	t.Run("overwrite with oversized value", func(t *testing.T) {
+		var evicted []cache.EvictReason
+		c := cache.NewTypedCostCache(10,
+			cache.WithSizer(func(_ string, value []byte) int64 {
+				return int64(len(value))
+			}),
+			cache.WithOnEvict(func(_ string, _ []byte, reason cache.EvictReason) {
+				evicted = append(evicted, reason)
+			}),
+		)
+		defer c.Close()
+
+		c.Set("aaa", []byte("old"))
+		c.Set("bbb", []byte("old"))
+
+		wasInCache := c.Set("aaa", make([]byte, 20))
+		require.True(t, wasInCache)
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		require.Equal(t, []cache.EvictReason{cache.EvictedCapacity}, evicted)
+
+		wasInCache, err := c.SetWithCost("bbb", []byte("new"), 11)
+		require.Truef(t, errors.Is(err, cache.ErrCostExceedsBudget), "actual err - %v", err)
+		require.True(t, wasInCache)
+		_, ok = c.Get("bbb")
+		require.False(t, ok)
+
+		require.False(t, c.Set("ccc", make([]byte, 20)))
+		require.Equal(t, int64(0), c.Stats().Cost)
+	})
+
+	t.Run("try set with sizer", func(t *testing.T) {
+		c := cache.NewTypedCostCache(10, cache.WithSizer(func(_ string, value []byte) int64 {
+			return int64(len(value))
+		}))
+		defer c.Close()
+
+		wasInCache, err := c.TrySet("aaa", make([]byte, 4))
+		require.NoError(t, err)
+		require.False(t, wasInCache)
+
+		wasInCache, err = c.TrySet("bbb", make([]byte, 20))
+		require.Truef(t, errors.Is(err, cache.ErrCostExceedsBudget), "actual err - %v", err)
+		require.False(t, wasInCache)
+
+		wasInCache, err = c.TrySet("aaa", make([]byte, 20))
+		require.Truef(t, errors.Is(err, cache.ErrCostExceedsBudget), "actual err - %v", err)
+		require.True(t, wasInCache)
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		require.Equal(t, int64(0), c.Stats().Cost)
+	})
+
+	t.Run("clear resets cost", func(t *testing.T) {
+		c := cache.NewCostCache(10)
+		defer c.Close()
+
+		c.Set("aaa", 100)
+		c.Set("bbb", 200)
+		require.Equal(t, int64(2), c.Stats().Cost)
+
+		c.Clear()
+		require.Equal(t, int64(0), c.Stats().Cost)
+	})
+}
@@ -0,0 +1,40 @@
+package cache
+
+import "github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+
+// ghostList remembers keys of recently evicted entries without their values.
+type ghostList[K comparable] struct {
+	keys  list.TypedList[K]
+	items map[K]*list.TypedItem[K]
+}
+
+func newGhostList[K comparable]() *ghostList[K] {
+	return &ghostList[K]{
+		keys:  list.NewTypedList[K](),
+		items: map[K]*list.TypedItem[K]{},
+	}
+}
+
+func (g *ghostList[K]) Len() int {
+	return g.keys.Len()
+}
+
+func (g *ghostList[K]) Push(key K) {
+	g.items[key] = g.keys.PushFront(key)
+}
+
+func (g *ghostList[K]) Remove(key K) bool {
+	el, ok := g.items[key]
+	if ok {
+		g.keys.Remove(el)
+		delete(g.items, key)
+	}
+	return ok
+}
+
+func (g *ghostList[K]) RemoveOldest() {
+	if el := g.keys.Back(); el != nil {
+		g.keys.Remove(el)
+		delete(g.items, el.Value)
+	}
+}
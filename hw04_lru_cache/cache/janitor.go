@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type janitor struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func startJanitor(interval time.Duration, sweep func()) *janitor {
+	j := &janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.stop:
+				return
+			case <-ticker.C:
+				sweep()
+			}
+		}
+	}()
+
+	return j
+}
+
+func (j *janitor) Stop() {
+	if j == nil {
+		return
+	}
+	j.once.Do(func() {
+		close(j.stop)
+	})
+	<-j.done
+}
@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+type lfuEntry[K comparable, V any] struct {
+	entry[K, V]
+	freq int
+}
+
+// lfuCache evicts the least frequently used entry. Entries with the same frequency
+// are kept in a list per frequency and evicted in LRU order.
+type lfuCache[K comparable, V any] struct {
+	core[K, V]
+	items   map[K]*list.TypedItem[lfuEntry[K, V]]
+	freqs   map[int]list.TypedList[lfuEntry[K, V]]
+	minFreq int
+}
+
+func (c *lfuCache[K, V]) Set(key K, value V) bool {
+	return c.SetWithTTL(key, value, 0)
+}
+
+func (c *lfuCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	expiresAt := expiration(ttl)
+	if el, ok := c.items[key]; ok {
+		if !el.Value.expired(time.Now()) {
+			el.Value.value = value
+			el.Value.expiresAt = expiresAt
+			c.touch(el)
+			return ok
+		}
+		c.remove(el, EvictedExpired)
+	}
+
+	if len(c.items) >= c.capacity && len(c.items) > 0 {
+		c.remove(c.freqs[c.leastFreq()].Back(), EvictedCapacity)
+	}
+
+	c.minFreq = 1
+	c.items[key] = c.bucket(1).PushFront(lfuEntry[K, V]{
+		entry: entry[K, V]{key: key, value: value, expiresAt: expiresAt},
+		freq:  1,
+	})
+
+	return false
+}
+
+func (c *lfuCache[K, V]) Get(key K) (V, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		if !el.Value.expired(time.Now()) {
+			c.hits++
+			value := el.Value.value
+			c.touch(el)
+			return value, ok
+		}
+		c.remove(el, EvictedExpired)
+	}
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+func (c *lfuCache[K, V]) Delete(key K) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if el.Value.expired(time.Now()) {
+		c.remove(el, EvictedExpired)
+		return false
+	}
+	c.remove(el, EvictedDeleted)
+	return true
+}
+
+func (c *lfuCache[K, V]) Clear() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.onEvict != nil {
+		for _, el := range c.items {
+			c.onEvict(el.Value.key, el.Value.value, EvictedCleared)
+		}
+	}
+	c.items = map[K]*list.TypedItem[lfuEntry[K, V]]{}
+	c.freqs = map[int]list.TypedList[lfuEntry[K, V]]{}
+	c.minFreq = 0
+}
+
+func (c *lfuCache[K, V]) Stats() Stats {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.stats(len(c.items))
+}
+
+func (c *lfuCache[K, V]) removeExpired() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := time.Now()
+	for _, el := range c.items {
+		if el.Value.expired(now) {
+			c.remove(el, EvictedExpired)
+		}
+	}
+}
+
+// touch moves the item to the list of the next frequency.
+func (c *lfuCache[K, V]) touch(el *list.TypedItem[lfuEntry[K, V]]) {
+	e := el.Value
+	c.unlink(el)
+	if c.minFreq == e.freq && c.freqs[e.freq] == nil {
+		c.minFreq++
+	}
+
+	e.freq++
+	c.items[e.key] = c.bucket(e.freq).PushFront(e)
+}
+
+func (c *lfuCache[K, V]) remove(el *list.TypedItem[lfuEntry[K, V]], reason EvictReason) {
+	c.unlink(el)
+	delete(c.items, el.Value.key)
+	c.evicted(&el.Value.entry, reason)
+}
+
+func (c *lfuCache[K, V]) unlink(el *list.TypedItem[lfuEntry[K, V]]) {
+	bucket := c.freqs[el.Value.freq]
+	bucket.Remove(el)
+	if bucket.Len() == 0 {
+		delete(c.freqs, el.Value.freq)
+	}
+}
+
+// leastFreq returns the lowest frequency present in the cache. minFreq is only a hint
+// because removing entries out of the eviction order may leave its list empty.
+func (c *lfuCache[K, V]) leastFreq() int {
+	if _, ok := c.freqs[c.minFreq]; ok {
+		return c.minFreq
+	}
+	c.minFreq = 0
+	for freq := range c.freqs {
+		if c.minFreq == 0 || freq < c.minFreq {
+			c.minFreq = freq
+		}
+	}
+	return c.minFreq
+}
+
+func (c *lfuCache[K, V]) bucket(freq int) list.TypedList[lfuEntry[K, V]] {
+	bucket, ok := c.freqs[freq]
+	if !ok {
+		bucket = list.NewTypedList[lfuEntry[K, V]]()
+		c.freqs[freq] = bucket
+	}
+	return bucket
+}
+
+func NewLFUCache(capacity int, opts ...Option[list.Key, interface{}]) Cache {
+	return NewTypedLFUCache[list.Key, interface{}](capacity, opts...)
+}
+
+func NewTypedLFUCache[K comparable, V any](capacity int, opts ...Option[K, V]) TypedCache[K, V] {
+	o := newOptions(opts)
+	c := &lfuCache[K, V]{
+		items: make(map[K]*list.TypedItem[lfuEntry[K, V]], capacity),
+		freqs: map[int]list.TypedList[lfuEntry[K, V]]{},
+	}
+	c.init(capacity, o)
+	c.startJanitor(o, c.removeExpired)
+	return c
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+type Loader[V any] func(ctx context.Context) (V, error)
+
+// LoadingCache loads missing values on demand. Concurrent GetOrLoad calls for the same key
+// share a single loader call: the first caller runs the loader with its context,
+// the others wait for the result or for their own context to be done. If the loader
+// stops because the context of the first caller is done, the others load the value again.
+type LoadingCache[K comparable, V any] interface {
+	TypedCache[K, V]
+	GetOrLoad(ctx context.Context, key K, loader Loader[V]) (V, error)
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loadingCache[K comparable, V any] struct {
+	TypedCache[K, V]
+	negative    TypedCache[K, error]
+	negativeTTL time.Duration
+
+	m     sync.Mutex
+	calls map[K]*call[V]
+}
+
+func (c *loadingCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V]) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	if c.negative != nil {
+		if err, ok := c.negative.Get(key); ok {
+			var zero V
+			return zero, err
+		}
+	}
+
+	c.m.Lock()
+	if cl, ok := c.calls[key]; ok {
+		c.m.Unlock()
+		select {
+		case <-cl.done:
+			if isContextError(cl.err) && ctx.Err() == nil {
+				return c.GetOrLoad(ctx, key, loader)
+			}
+			return cl.value, cl.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+	// The error is kept for the waiters if the loader panics.
+	cl := &call[V]{done: make(chan struct{}), err: ErrLoaderPanicked}
+	c.calls[key] = cl
+	c.m.Unlock()
+
+	defer func() {
+		c.m.Lock()
+		delete(c.calls, key)
+		c.m.Unlock()
+		close(cl.done)
+	}()
+
+	cl.value, cl.err = loader(ctx)
+	if cl.err == nil {
+		c.Set(key, cl.value)
+	} else if c.negative != nil && !isContextError(cl.err) {
+		c.negative.SetWithTTL(key, cl.err, c.negativeTTL)
+	}
+
+	return cl.value, cl.err
+}
+
+// isContextError reports if the loader has stopped because of the context of the first caller.
+// Such errors are not cached, the later callers with live contexts must call the loader again.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (c *loadingCache[K, V]) Delete(key K) bool {
+	if c.negative != nil {
+		c.negative.Delete(key)
+	}
+	return c.TypedCache.Delete(key)
+}
+
+func (c *loadingCache[K, V]) Clear() {
+	if c.negative != nil {
+		c.negative.Clear()
+	}
+	c.TypedCache.Clear()
+}
+
+func (c *loadingCache[K, V]) Close() {
+	if c.negative != nil {
+		c.negative.Close()
+	}
+	c.TypedCache.Close()
+}
+
+func NewLoadingCache(c Cache, opts ...Option[list.Key, interface{}]) LoadingCache[list.Key, interface{}] {
+	return NewTypedLoadingCache(c, opts...)
+}
+
+// NewTypedLoadingCache adds GetOrLoad to the cache. Loader errors are not cached
+// unless WithNegativeCaching is set.
+func NewTypedLoadingCache[K comparable, V any](c TypedCache[K, V], opts ...Option[K, V]) LoadingCache[K, V] {
+	o := newOptions(opts)
+	lc := &loadingCache[K, V]{
+		TypedCache: c,
+		calls:      map[K]*call[V]{},
+	}
+	if o.negativeCapacity > 0 && o.negativeTTL > 0 {
+		lc.negative = NewTypedCache[K, error](o.negativeCapacity)
+		lc.negativeTTL = o.negativeTTL
+	}
+	return lc
+}
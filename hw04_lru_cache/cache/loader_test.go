@@ -0,0 +1,261 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/cache"
+)
+
+var errLoad = errors.New("load failed")
+
+// notifyingCache reports every Get, so the test knows the caller has not found the value yet.
+type notifyingCache struct {
+	cache.TypedCache[string, int]
+	got chan struct{}
+}
+
+func (c notifyingCache) Get(key string) (int, bool) {
+	value, ok := c.TypedCache.Get(key)
+	select {
+	case c.got <- struct{}{}:
+	default:
+	}
+	return value, ok
+}
+
+func TestLoadingCache(t *testing.T) {
+	t.Run("loads once", func(t *testing.T) {
+		c := cache.NewTypedLoadingCache(cache.NewTypedCache[string, int](10))
+		defer c.Close()
+
+		var calls int32
+		release := make(chan struct{})
+		loader := func(context.Context) (int, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return 100, nil
+		}
+
+		const callers = 10
+		wg := sync.WaitGroup{}
+		results := make([]int, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				val, err := c.GetOrLoad(context.Background(), "aaa", loader)
+				require.NoError(t, err)
+				results[i] = val
+			}()
+		}
+
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&calls) == 1
+		}, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		for _, val := range results {
+			require.Equal(t, 100, val)
+		}
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+	})
+
+	t.Run("cached value", func(t *testing.T) {
+		c := cache.NewLoadingCache(cache.NewCache(10))
+		defer c.Close()
+
+		c.Set("aaa", 100)
+		val, err := c.GetOrLoad(context.Background(), "aaa", func(context.Context) (interface{}, error) {
+			return nil, errLoad
+		})
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		c := cache.NewTypedLoadingCache(cache.NewTypedCache[string, int](10))
+		defer c.Close()
+
+		_, err := c.GetOrLoad(context.Background(), "aaa", func(context.Context) (int, error) {
+			return 0, errLoad
+		})
+		require.Truef(t, errors.Is(err, errLoad), "actual err - %v", err)
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+
+		val, err := c.GetOrLoad(context.Background(), "aaa", func(context.Context) (int, error) {
+			return 100, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+	})
+
+	t.Run("negative caching", func(t *testing.T) {
+		c := cache.NewTypedLoadingCache(cache.NewTypedCache[string, int](10),
+			cache.WithNegativeCaching[string, int](10, 20*time.Millisecond))
+		defer c.Close()
+
+		var calls int
+		loader := func(context.Context) (int, error) {
+			calls++
+			return 0, errLoad
+		}
+
+		for i := 0; i < 3; i++ {
+			_, err := c.GetOrLoad(context.Background(), "aaa", loader)
+			require.Truef(t, errors.Is(err, errLoad), "actual err - %v", err)
+		}
+		require.Equal(t, 1, calls)
+
+		time.Sleep(30 * time.Millisecond)
+		_, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.Truef(t, errors.Is(err, errLoad), "actual err - %v", err)
+		require.Equal(t, 2, calls)
+
+		c.Delete("aaa")
+		_, err = c.GetOrLoad(context.Background(), "aaa", loader)
+		require.Truef(t, errors.Is(err, errLoad), "actual err - %v", err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("context errors are not cached", func(t *testing.T) {
+		c := cache.NewTypedLoadingCache(cache.NewTypedCache[string, int](10),
+			cache.WithNegativeCaching[string, int](10, time.Minute))
+		defer c.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.GetOrLoad(ctx, "aaa", func(ctx context.Context) (int, error) {
+			return 0, ctx.Err()
+		})
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+
+		ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, err = c.GetOrLoad(ctx, "aaa", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, fmt.Errorf("load: %w", ctx.Err())
+		})
+		require.Truef(t, errors.Is(err, context.DeadlineExceeded), "actual err - %v", err)
+
+		var calls int
+		val, err := c.GetOrLoad(context.Background(), "aaa", func(context.Context) (int, error) {
+			calls++
+			return 100, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("waiter context", func(t *testing.T) {
+		c := cache.NewTypedLoadingCache(cache.NewTypedCache[string, int](10))
+		defer c.Close()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			c.GetOrLoad(context.Background(), "aaa", func(context.Context) (int, error) {
+				close(started)
+				<-release
+				return 100, nil
+			})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := c.GetOrLoad(ctx, "aaa", func(context.Context) (int, error) {
+			return 200, nil
+		})
+		require.Truef(t, errors.Is(err, context.DeadlineExceeded), "actual err - %v", err)
+
+		close(release)
+		require.Eventually(t, func() bool {
+			val, ok := c.Get("aaa")
+			return ok && val == 100
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("waiter loads again after first caller context", func(t *testing.T) {
+		got := make(chan struct{}, 2)
+		c := cache.NewTypedLoadingCache[string, int](notifyingCache{cache.NewTypedCache[string, int](10), got})
+		defer c.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		firstErr := make(chan error)
+		go func() {
+			_, err := c.GetOrLoad(ctx, "aaa", func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			})
+			firstErr <- err
+		}()
+		<-got
+
+		result := make(chan int)
+		go func() {
+			val, err := c.GetOrLoad(context.Background(), "aaa", func(context.Context) (int, error) {
+				return 100, nil
+			})
+			if err != nil {
+				val = -1
+			}
+			result <- val
+		}()
+		<-got
+
+		cancel()
+		err := <-firstErr
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+		require.Equal(t, 100, <-result)
+	})
+
+	t.Run("loader panic", func(t *testing.T) {
+		c := cache.NewTypedLoadingCache(cache.NewTypedCache[string, int](10))
+		defer c.Close()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			defer func() {
+				_ = recover()
+			}()
+			c.GetOrLoad(context.Background(), "aaa", func(context.Context) (int, error) {
+				close(started)
+				<-release
+				panic("boom")
+			})
+		}()
+		<-started
+
+		errCh := make(chan error)
+		go func() {
+			_, err := c.GetOrLoad(context.Background(), "aaa", func(context.Context) (int, error) {
+				return 0, errLoad
+			})
+			errCh <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+
+		err := <-errCh
+		require.Truef(t, errors.Is(err, cache.ErrLoaderPanicked), "actual err - %v", err)
+	})
+}
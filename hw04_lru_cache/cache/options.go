@@ -0,0 +1,66 @@
+package cache
+
+import "time"
+
+type options[K comparable, V any] struct {
+	janitorInterval time.Duration
+	onEvict         func(key K, value V, reason EvictReason)
+	sizer           Sizer[K, V]
+
+	negativeCapacity int
+	negativeTTL      time.Duration
+
+	codec Codec
+}
+
+type Option[K comparable, V any] func(*options[K, V])
+
+// WithJanitor starts a background goroutine that removes expired entries every interval.
+// The goroutine is stopped by Close.
+func WithJanitor[K comparable, V any](interval time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.janitorInterval = interval
+	}
+}
+
+// WithOnEvict sets a callback called for every entry leaving the cache.
+// The callback is called with the cache locked and must not call the cache methods.
+func WithOnEvict[K comparable, V any](fn func(key K, value V, reason EvictReason)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onEvict = fn
+	}
+}
+
+// WithSizer sets a function computing the cost of the entries stored without an explicit cost.
+// It is used only by the caches created with NewTypedCostCache.
+func WithSizer[K comparable, V any](sizer Sizer[K, V]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.sizer = sizer
+	}
+}
+
+// WithNegativeCaching makes the loading cache remember up to capacity loader errors for ttl,
+// so GetOrLoad returns them without calling the loader again. Context errors are not remembered.
+// It is used only by the caches created with NewTypedLoadingCache.
+func WithNegativeCaching[K comparable, V any](capacity int, ttl time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.negativeCapacity = capacity
+		o.negativeTTL = ttl
+	}
+}
+
+// WithCodec sets the codec used by Dump and Load. GobCodec is used by default.
+// JSONCodec does not keep the types of the interface values, see JSONCodec.
+func WithCodec[K comparable, V any](codec Codec) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.codec = codec
+	}
+}
+
+func newOptions[K comparable, V any](opts []Option[K, V]) options[K, V] {
+	o := options[K, V]{codec: GobCodec}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,237 @@
+package cache_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/cache"
+)
+
+type constructor func(capacity int, opts ...cache.Option[string, int]) cache.TypedCache[string, int]
+
+var policies = map[string]constructor{
+	"LRU": cache.NewTypedCache[string, int],
+	"sharded": func(capacity int, opts ...cache.Option[string, int]) cache.TypedCache[string, int] {
+		return cache.NewTypedShardedCache(1, capacity, opts...)
+	},
+	"LFU": cache.NewTypedLFUCache[string, int],
+	"2Q":  cache.NewTyped2QCache[string, int],
+	"ARC": cache.NewTypedARCCache[string, int],
+}
+
+// TestPolicies is the conformance suite every cache.TypedCache implementation must pass.
+func TestPolicies(t *testing.T) {
+	for name, newCache := range policies {
+		t.Run(name, func(t *testing.T) {
+			testPolicy(t, newCache)
+		})
+	}
+}
+
+func testPolicy(t *testing.T, newCache constructor) {
+	t.Helper()
+
+	t.Run("empty cache", func(t *testing.T) {
+		c := newCache(10)
+		defer c.Close()
+
+		val, ok := c.Get("aaa")
+		require.False(t, ok)
+		require.Zero(t, val)
+	})
+
+	t.Run("set and get", func(t *testing.T) {
+		c := newCache(5)
+		defer c.Close()
+
+		require.False(t, c.Set("aaa", 100))
+		require.False(t, c.Set("bbb", 200))
+		require.True(t, c.Set("aaa", 300))
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+
+		val, ok = c.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		const capacity = 8
+		reasons := map[cache.EvictReason]int{}
+		c := newCache(capacity, cache.WithOnEvict(func(_ string, _ int, reason cache.EvictReason) {
+			reasons[reason]++
+		}))
+		defer c.Close()
+
+		for i := 0; i < 3*capacity; i++ {
+			c.Set(strconv.Itoa(i), i)
+			c.Get(strconv.Itoa(i / 2))
+
+			require.LessOrEqual(t, c.Stats().Size, capacity)
+		}
+
+		val, ok := c.Get(strconv.Itoa(3*capacity - 1))
+		require.True(t, ok)
+		require.Equal(t, 3*capacity-1, val)
+
+		stats := c.Stats()
+		require.Equal(t, capacity, stats.Size)
+		require.Equal(t, capacity, stats.Capacity)
+		require.Equal(t, uint64(2*capacity), stats.Evictions)
+		require.Equal(t, map[cache.EvictReason]int{cache.EvictedCapacity: 2 * capacity}, reasons)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		var deleted []string
+		c := newCache(5, cache.WithOnEvict(func(key string, _ int, reason cache.EvictReason) {
+			require.Equal(t, cache.EvictedDeleted, reason)
+			deleted = append(deleted, key)
+		}))
+		defer c.Close()
+
+		c.Set("aaa", 100)
+		c.Get("aaa")
+
+		require.True(t, c.Delete("aaa"))
+		require.False(t, c.Delete("aaa"))
+		require.False(t, c.Delete("bbb"))
+		require.Equal(t, []string{"aaa"}, deleted)
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		require.False(t, c.Set("aaa", 200))
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		cleared := map[string]int{}
+		c := newCache(5, cache.WithOnEvict(func(key string, value int, reason cache.EvictReason) {
+			require.Equal(t, cache.EvictedCleared, reason)
+			cleared[key] = value
+		}))
+		defer c.Close()
+
+		c.Set("aaa", 100)
+		c.Set("bbb", 200)
+		c.Get("bbb")
+		c.Clear()
+
+		require.Equal(t, map[string]int{"aaa": 100, "bbb": 200}, cleared)
+		require.Equal(t, 0, c.Stats().Size)
+		_, ok := c.Get("bbb")
+		require.False(t, ok)
+		require.False(t, c.Set("aaa", 300))
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		c := newCache(5)
+		defer c.Close()
+
+		c.SetWithTTL("aaa", 100, 10*time.Millisecond)
+		c.Set("bbb", 200)
+		time.Sleep(20 * time.Millisecond)
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		_, ok = c.Get("bbb")
+		require.True(t, ok)
+
+		stats := c.Stats()
+		require.Equal(t, uint64(1), stats.Hits)
+		require.Equal(t, uint64(1), stats.Misses)
+		require.Equal(t, uint64(1), stats.Evictions)
+		require.Equal(t, 1, stats.Size)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		c := newCache(5, cache.WithJanitor[string, int](5*time.Millisecond))
+		defer c.Close()
+
+		c.SetWithTTL("aaa", 100, 10*time.Millisecond)
+		c.Get("aaa")
+		c.SetWithTTL("bbb", 200, 10*time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			return c.Stats().Size == 0
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("multithreading", func(_ *testing.T) {
+		c := newCache(10)
+		defer c.Close()
+		wg := &sync.WaitGroup{}
+
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 10_000; i++ {
+					key := strconv.Itoa(i % 30)
+					c.Set(key, i)
+					c.Get(key)
+					if i%7 == 0 {
+						c.Delete(key)
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestLFUCache(t *testing.T) {
+	c := cache.NewTypedLFUCache[string, int](3)
+	defer c.Close()
+
+	c.Set("aaa", 100)
+	c.Set("bbb", 200)
+	c.Set("ccc", 300)
+
+	c.Get("aaa")
+	c.Get("aaa")
+	c.Get("bbb")
+	c.Get("ccc")
+	c.Get("ccc")
+
+	c.Set("ddd", 400) // evicts "bbb" used twice
+
+	_, ok := c.Get("bbb")
+	require.False(t, ok)
+	for _, key := range []string{"aaa", "ccc", "ddd"} {
+		_, ok = c.Get(key)
+		require.True(t, ok, key)
+	}
+}
+
+func TestScanResistance(t *testing.T) {
+	for name, newCache := range map[string]constructor{
+		"2Q":  cache.NewTyped2QCache[string, int],
+		"ARC": cache.NewTypedARCCache[string, int],
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := newCache(20)
+			defer c.Close()
+
+			hot := []string{"aaa", "bbb", "ccc", "ddd"}
+			for i, key := range hot {
+				c.Set(key, i)
+				c.Get(key)
+			}
+
+			for i := 0; i < 100; i++ {
+				c.Set("scan"+strconv.Itoa(i), i)
+			}
+
+			for _, key := range hot {
+				_, ok := c.Get(key)
+				require.True(t, ok, key)
+			}
+		})
+	}
+}
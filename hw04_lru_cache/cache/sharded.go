@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+// shardedCache splits keys between independent lruCache shards, so goroutines
+// working with different shards do not wait for each other.
+type shardedCache[K comparable, V any] struct {
+	shards  []*lruCache[K, V]
+	seed    maphash.Seed
+	janitor *janitor
+}
+
+func (c *shardedCache[K, V]) Set(key K, value V) bool {
+	return c.shard(key).Set(key, value)
+}
+
+func (c *shardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	return c.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (c *shardedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *shardedCache[K, V]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+func (c *shardedCache[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+func (c *shardedCache[K, V]) Close() {
+	c.janitor.Stop()
+}
+
+func (c *shardedCache[K, V]) Stats() Stats {
+	var stats Stats
+	for _, shard := range c.shards {
+		s := shard.Stats()
+		stats.Hits += s.Hits
+		stats.Misses += s.Misses
+		stats.Evictions += s.Evictions
+		stats.Size += s.Size
+		stats.Capacity += s.Capacity
+	}
+	return stats
+}
+
+func (c *shardedCache[K, V]) removeExpired() {
+	for _, shard := range c.shards {
+		shard.removeExpired()
+	}
+}
+
+func (c *shardedCache[K, V]) shard(key K) *lruCache[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+func NewShardedCache(shards, capacity int, opts ...Option[list.Key, interface{}]) Cache {
+	return NewTypedShardedCache[list.Key, interface{}](shards, capacity, opts...)
+}
+
+// NewTypedShardedCache creates a cache of the given total capacity divided between shards.
+// The capacity of each shard is rounded up, so the total capacity may be slightly bigger.
+// The shards share one lock for the onEvict callback, so it is never called concurrently.
+func NewTypedShardedCache[K comparable, V any](shards, capacity int, opts ...Option[K, V]) TypedCache[K, V] {
+	if shards <= 0 {
+		shards = 1
+	}
+
+	o := newOptions(opts)
+	if onEvict := o.onEvict; onEvict != nil {
+		m := sync.Mutex{}
+		o.onEvict = func(key K, value V, reason EvictReason) {
+			m.Lock()
+			defer m.Unlock()
+			onEvict(key, value, reason)
+		}
+	}
+	c := &shardedCache[K, V]{
+		shards: make([]*lruCache[K, V], shards),
+		seed:   maphash.MakeSeed(),
+	}
+	shardCapacity := (capacity + shards - 1) / shards
+	for i := range c.shards {
+		c.shards[i] = newLRUCache(shardCapacity, o)
+	}
+	if o.janitorInterval > 0 {
+		c.janitor = startJanitor(o.janitorInterval, c.removeExpired)
+	}
+	return c
+}
@@ -0,0 +1,148 @@
+package cache_test
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/cache"
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+func TestShardedCache(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		// Every shard can hold all the keys, so nothing is evicted whatever the distribution is.
+		c := cache.NewShardedCache(4, 400)
+		defer c.Close()
+
+		for i := 0; i < 100; i++ {
+			wasInCache := c.Set(list.Key(strconv.Itoa(i)), i)
+			require.False(t, wasInCache)
+		}
+
+		wasInCache := c.Set("1", 300)
+		require.True(t, wasInCache)
+
+		val, ok := c.Get("1")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+
+		require.True(t, c.Delete("1"))
+		_, ok = c.Get("1")
+		require.False(t, ok)
+
+		c.Clear()
+		_, ok = c.Get("2")
+		require.False(t, ok)
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		c := cache.NewTypedShardedCache[int, int](3, 10)
+		defer c.Close()
+
+		for i := 0; i < 100; i++ {
+			c.Set(i, i)
+		}
+
+		stats := c.Stats()
+		require.Equal(t, 12, stats.Capacity)
+		require.LessOrEqual(t, stats.Size, stats.Capacity)
+		require.Equal(t, uint64(100-stats.Size), stats.Evictions)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		c := cache.NewTypedShardedCache(2, 10, cache.WithJanitor[int, int](10*time.Millisecond))
+		defer c.Close()
+
+		for i := 0; i < 10; i++ {
+			c.SetWithTTL(i, i, 20*time.Millisecond)
+		}
+
+		require.Eventually(t, func() bool {
+			return c.Stats().Size == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("on evict is not called concurrently", func(t *testing.T) {
+		// The callback does not lock, the race detector fails the test on concurrent calls.
+		var evicted []int
+		c := cache.NewTypedShardedCache(8, 8, cache.WithOnEvict(func(key, _ int, _ cache.EvictReason) {
+			evicted = append(evicted, key)
+		}))
+		defer c.Close()
+
+		wg := sync.WaitGroup{}
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					c.Set(g*1000+i, i)
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, 8000-c.Stats().Size, len(evicted))
+	})
+}
+
+func TestShardedCacheMultithreading(_ *testing.T) {
+	c := cache.NewShardedCache(8, 10)
+	defer c.Close()
+	wg := &sync.WaitGroup{}
+
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100_000; i++ {
+				key := list.Key(strconv.Itoa(i % 50))
+				c.Set(key, i)
+				c.Get(key)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkCacheParallel(b *testing.B) {
+	const size = 10_000
+	keys := make([]list.Key, size)
+	for i := range keys {
+		keys[i] = list.Key(strconv.Itoa(i))
+	}
+
+	caches := []struct {
+		name string
+		c    cache.Cache
+	}{
+		{name: "single lock", c: cache.NewCache(size)},
+		{name: "sharded", c: cache.NewShardedCache(32, size)},
+	}
+
+	for _, tc := range caches {
+		for i, key := range keys {
+			tc.c.Set(key, i)
+		}
+
+		b.Run(tc.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := rand.Intn(size)
+				for pb.Next() {
+					if i%10 == 0 {
+						tc.c.Set(keys[i%size], i)
+					} else {
+						tc.c.Get(keys[i%size])
+					}
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	GobCodec Codec = gobCodec{}
+	// JSONCodec needs the concrete types of the keys and values. The interface values,
+	// as in the cache created with NewCache, are loaded as JSON decodes them into interface{},
+	// e.g. numbers become float64.
+	JSONCodec Codec = jsonCodec{}
+)
+
+type (
+	Encoder interface {
+		Encode(v any) error
+	}
+
+	Decoder interface {
+		Decode(v any) error
+	}
+
+	Codec interface {
+		NewEncoder(w io.Writer) Encoder
+		NewDecoder(r io.Reader) Decoder
+	}
+
+	gobCodec  struct{}
+	jsonCodec struct{}
+)
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+func (gobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// Snapshotter is implemented by the caches created with NewTypedCache and NewTypedCostCache.
+// Dump writes the entries from the least to the most recently used one, and Load sets them
+// in the same order, so the loaded cache keeps the recency order and a smaller cache keeps
+// only the most recent entries. Expired entries are skipped. Loaded entries are added
+// to the current cache contents.
+type Snapshotter interface {
+	Dump(w io.Writer) error
+	Load(r io.Reader) error
+}
+
+type snapshotEntry[K comparable, V any] struct {
+	Key       K         `json:"key"`
+	Value     V         `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Cost      int64     `json:"cost"`
+}
+
+func (c *lruCache[K, V]) Dump(w io.Writer) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	enc := c.codec.NewEncoder(w)
+	now := time.Now()
+	for el := c.queue.Back(); el != nil; el = el.Prev {
+		if el.Value.expired(now) {
+			continue
+		}
+		err := enc.Encode(snapshotEntry[K, V]{
+			Key:       el.Value.key,
+			Value:     el.Value.value,
+			ExpiresAt: el.Value.expiresAt,
+			Cost:      el.Value.cost,
+		})
+		if err != nil {
+			return fmt.Errorf("encode entry: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *lruCache[K, V]) Load(r io.Reader) error {
+	dec := c.codec.NewDecoder(r)
+	for {
+		var e snapshotEntry[K, V]
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("decode entry: %w", err)
+		}
+
+		c.load(e)
+	}
+}
+
+func (c *lruCache[K, V]) load(e snapshotEntry[K, V]) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if !e.ExpiresAt.IsZero() && !time.Now().Before(e.ExpiresAt) {
+		return
+	}
+	if c.maxCost <= 0 {
+		e.Cost = 0
+	} else if e.Cost == 0 {
+		e.Cost = c.costOf(e.Key, e.Value)
+	}
+	// Entries not fitting into the budget of this cache are skipped.
+	_, _ = c.set(e.Key, e.Value, e.Cost, e.ExpiresAt)
+}
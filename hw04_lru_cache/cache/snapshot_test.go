@@ -0,0 +1,157 @@
+package cache_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/cache"
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+func TestSnapshot(t *testing.T) {
+	for name, codec := range map[string]cache.Codec{"gob": cache.GobCodec, "json": cache.JSONCodec} {
+		t.Run(name, func(t *testing.T) {
+			t.Run("keeps recency order", func(t *testing.T) {
+				src := cache.NewTypedCache(3, cache.WithCodec[string, int](codec))
+				defer src.Close()
+				src.Set("aaa", 100)
+				src.Set("bbb", 200)
+				src.Set("ccc", 300)
+				src.Get("aaa") // [aaa, ccc, bbb]
+
+				buf := &bytes.Buffer{}
+				require.NoError(t, src.(cache.Snapshotter).Dump(buf))
+
+				dst := cache.NewTypedCache(3, cache.WithCodec[string, int](codec))
+				defer dst.Close()
+				require.NoError(t, dst.(cache.Snapshotter).Load(buf))
+
+				dst.Set("ddd", 400) // evicts "bbb"
+
+				for key, expected := range map[string]int{"aaa": 100, "ccc": 300, "ddd": 400} {
+					val, ok := dst.Get(key)
+					require.True(t, ok, key)
+					require.Equal(t, expected, val)
+				}
+				_, ok := dst.Get("bbb")
+				require.False(t, ok)
+			})
+
+			t.Run("smaller cache", func(t *testing.T) {
+				src := cache.NewTypedCache(5, cache.WithCodec[string, int](codec))
+				defer src.Close()
+				for i, key := range []string{"aaa", "bbb", "ccc", "ddd", "eee"} {
+					src.Set(key, i)
+				}
+
+				buf := &bytes.Buffer{}
+				require.NoError(t, src.(cache.Snapshotter).Dump(buf))
+
+				dst := cache.NewTypedCache(2, cache.WithCodec[string, int](codec))
+				defer dst.Close()
+				require.NoError(t, dst.(cache.Snapshotter).Load(buf))
+
+				require.Equal(t, 2, dst.Stats().Size)
+				_, ok := dst.Get("ddd")
+				require.True(t, ok)
+				_, ok = dst.Get("eee")
+				require.True(t, ok)
+			})
+
+			t.Run("ttl", func(t *testing.T) {
+				src := cache.NewTypedCache(5, cache.WithCodec[string, int](codec))
+				defer src.Close()
+				src.SetWithTTL("aaa", 100, 10*time.Millisecond)
+				src.SetWithTTL("bbb", 200, time.Hour)
+				src.SetWithTTL("ccc", 300, 30*time.Millisecond)
+
+				buf := &bytes.Buffer{}
+				require.NoError(t, src.(cache.Snapshotter).Dump(buf))
+				time.Sleep(20 * time.Millisecond)
+
+				dst := cache.NewTypedCache(5, cache.WithCodec[string, int](codec))
+				defer dst.Close()
+				require.NoError(t, dst.(cache.Snapshotter).Load(buf))
+				require.Equal(t, 2, dst.Stats().Size)
+
+				time.Sleep(20 * time.Millisecond)
+				_, ok := dst.Get("ccc")
+				require.False(t, ok)
+				_, ok = dst.Get("bbb")
+				require.True(t, ok)
+			})
+		})
+	}
+
+	t.Run("cost", func(t *testing.T) {
+		src := cache.NewTypedCostCache[string, int](10)
+		defer src.Close()
+		src.SetWithCost("aaa", 100, 6)
+		src.SetWithCost("bbb", 200, 4)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, src.(cache.Snapshotter).Dump(buf))
+
+		dst := cache.NewTypedCostCache[string, int](5)
+		defer dst.Close()
+		require.NoError(t, dst.(cache.Snapshotter).Load(buf))
+
+		_, ok := dst.Get("aaa")
+		require.False(t, ok)
+		_, ok = dst.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, int64(4), dst.Stats().Cost)
+	})
+
+	t.Run("untyped cache", func(t *testing.T) {
+		src := cache.NewCache(3)
+		defer src.Close()
+		src.Set("aaa", 100)
+		src.Set("bbb", "bbb")
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, src.(cache.Snapshotter).Dump(buf))
+
+		dst := cache.NewCache(3)
+		defer dst.Close()
+		require.NoError(t, dst.(cache.Snapshotter).Load(buf))
+
+		val, ok := dst.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+		val, ok = dst.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, "bbb", val)
+
+		// JSON does not keep the types of the interface values.
+		src = cache.NewCache(3, cache.WithCodec[list.Key, interface{}](cache.JSONCodec))
+		defer src.Close()
+		src.Set("aaa", 100)
+		src.Set("bbb", "bbb")
+
+		buf.Reset()
+		require.NoError(t, src.(cache.Snapshotter).Dump(buf))
+
+		dst = cache.NewCache(3, cache.WithCodec[list.Key, interface{}](cache.JSONCodec))
+		defer dst.Close()
+		require.NoError(t, dst.(cache.Snapshotter).Load(buf))
+
+		val, ok = dst.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, float64(100), val)
+		val, ok = dst.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, "bbb", val)
+	})
+
+	t.Run("broken input", func(t *testing.T) {
+		c := cache.NewCache(3, cache.WithCodec[list.Key, interface{}](cache.JSONCodec))
+		defer c.Close()
+
+		err := c.(cache.Snapshotter).Load(bytes.NewBufferString(`{"key": 1}`))
+		require.Error(t, err)
+	})
+}
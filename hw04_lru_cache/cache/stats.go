@@ -0,0 +1,38 @@
+package cache
+
+type EvictReason int
+
+const (
+	EvictedCapacity EvictReason = iota
+	EvictedExpired
+	EvictedDeleted
+	EvictedCleared
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedExpired:
+		return "expired"
+	case EvictedDeleted:
+		return "deleted"
+	case EvictedCleared:
+		return "cleared"
+	default:
+		return "unknown"
+	}
+}
+
+// Stats is a snapshot of cache counters. Evictions counts only entries removed
+// by the cache itself, i.e. because of capacity or expiration.
+// Cost and MaxCost are filled only by the caches created with NewTypedCostCache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+	Capacity  int
+	Cost      int64
+	MaxCost   int64
+}
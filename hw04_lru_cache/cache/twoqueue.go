@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+const (
+	twoQueueRecentRatio = 0.25
+	twoQueueGhostRatio  = 0.5
+)
+
+// twoQueueCache implements the 2Q policy. New entries get into the recent FIFO queue and are
+// promoted to the frequent LRU queue on the second access, so a single scan cannot push out
+// the frequently used entries. Keys evicted from the recent queue are remembered in the ghost
+// queue, and they go straight to the frequent queue when set again.
+type twoQueueCache[K comparable, V any] struct {
+	queues[K, V]
+	recentSize int
+	ghostSize  int
+
+	ghost *ghostList[K]
+}
+
+func (c *twoQueueCache[K, V]) Set(key K, value V) bool {
+	return c.SetWithTTL(key, value, 0)
+}
+
+func (c *twoQueueCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e := entry[K, V]{key: key, value: value, expiresAt: expiration(ttl)}
+	if c.update(e) {
+		return true
+	}
+
+	fromGhost := c.ghost.Remove(key)
+	c.ensureSpace(fromGhost)
+	c.push(e, fromGhost)
+	return false
+}
+
+// ensureSpace frees a slot for a new entry. It prefers the recent queue while it is larger
+// than its target size.
+func (c *twoQueueCache[K, V]) ensureSpace(fromGhost bool) {
+	if !c.full() {
+		return
+	}
+
+	recentLen := c.recent.Len()
+	if recentLen > 0 && (recentLen > c.recentSize || (recentLen == c.recentSize && !fromGhost) ||
+		c.frequent.Len() == 0) {
+		el := c.recent.Back()
+		c.remove(el, EvictedCapacity)
+
+		if c.ghost.Len() >= c.ghostSize {
+			c.ghost.RemoveOldest()
+		}
+		if c.ghostSize > 0 {
+			c.ghost.Push(el.Value.key)
+		}
+		return
+	}
+
+	c.remove(c.frequent.Back(), EvictedCapacity)
+}
+
+func (c *twoQueueCache[K, V]) resetGhost() {
+	c.ghost = newGhostList[K]()
+}
+
+func New2QCache(capacity int, opts ...Option[list.Key, interface{}]) Cache {
+	return NewTyped2QCache[list.Key, interface{}](capacity, opts...)
+}
+
+func NewTyped2QCache[K comparable, V any](capacity int, opts ...Option[K, V]) TypedCache[K, V] {
+	o := newOptions(opts)
+	c := &twoQueueCache[K, V]{
+		recentSize: int(float64(capacity) * twoQueueRecentRatio),
+		ghostSize:  int(float64(capacity) * twoQueueGhostRatio),
+	}
+	c.init(capacity, o)
+	c.resetPolicy = c.resetGhost
+	c.reset()
+	c.startJanitor(o, c.removeExpired)
+	return c
+}
@@ -1,6 +1,6 @@
 module github.com/AnnDutova/otus_go_hw/hw04_lru_cache
 
-go 1.22.4
+go 1.24
 
 require github.com/stretchr/testify v1.7.0
 
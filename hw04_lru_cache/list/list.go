@@ -1,5 +1,7 @@
 package list
 
+import "iter"
+
 type (
 	List interface {
 		Len() int
@@ -7,8 +9,18 @@ type (
 		Back() *Item
 		PushFront(v interface{}) *Item
 		PushBack(v interface{}) *Item
+		PushFrontList(other List)
+		PushBackList(other List)
+		InsertBefore(v interface{}, mark *Item) *Item
+		InsertAfter(v interface{}, mark *Item) *Item
 		Remove(i *Item)
 		MoveToFront(i *Item)
+		MoveToBack(i *Item)
+		MoveBefore(i, mark *Item)
+		MoveAfter(i, mark *Item)
+		All() iter.Seq[*Item]
+		Backward() iter.Seq[*Item]
+		Values() iter.Seq[interface{}]
 	}
 
 	Key  string
@@ -17,6 +29,8 @@ type (
 		Key   Key
 		Next  *Item
 		Prev  *Item
+
+		list *list // The list the item belongs to, nil once removed.
 	}
 
 	list struct {
@@ -39,85 +53,163 @@ func (l *list) Back() *Item {
 }
 
 func (l *list) PushFront(v interface{}) *Item {
-	item := &Item{Value: v, Next: nil, Prev: nil}
-	if l.head == nil {
-		l.head = item
-		l.tail = item
-	} else {
-		item.Next = l.head
-		l.head.Prev = item
-		l.head = item
-	}
-	l.len++
-	return l.head
+	return l.insert(&Item{Value: v}, nil, l.head)
 }
 
 func (l *list) PushBack(v interface{}) *Item {
-	newTail := &Item{Value: v, Next: nil, Prev: l.tail}
-	if l.head == nil {
-		l.head = newTail
-		l.tail = l.head
-	} else {
-		l.tail.Next = newTail
-		l.tail = newTail
+	return l.insert(&Item{Value: v}, l.tail, nil)
+}
+
+// PushFrontList inserts a copy of the other list at the front. The lists may be the same.
+func (l *list) PushFrontList(other List) {
+	for n, i := other.Len(), other.Back(); n > 0; n, i = n-1, i.Prev {
+		l.PushFront(i.Value)
 	}
+}
 
-	l.len++
-	return l.tail
+// PushBackList inserts a copy of the other list at the back. The lists may be the same.
+func (l *list) PushBackList(other List) {
+	for n, i := other.Len(), other.Front(); n > 0; n, i = n-1, i.Next {
+		l.PushBack(i.Value)
+	}
+}
+
+// InsertBefore inserts v before mark. It returns nil if mark is not in the list.
+func (l *list) InsertBefore(v interface{}, mark *Item) *Item {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Item{Value: v}, mark.Prev, mark)
+}
+
+// InsertAfter inserts v after mark. It returns nil if mark is not in the list.
+func (l *list) InsertAfter(v interface{}, mark *Item) *Item {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Item{Value: v}, mark, mark.Next)
 }
 
+// Remove removes i from the list. Items that are not in the list, including already removed ones, are ignored.
 func (l *list) Remove(i *Item) {
-	elem := l.head
-	for elem != nil {
-		if elem.Value == i.Value {
-			switch {
-			case l.head == elem:
-				newHead := l.head.Next
-				l.head = newHead
-			case l.tail == elem:
-				l.tail = elem.Prev
-				elem.Prev.Next = nil
-			default:
-				pr := elem.Prev
-				pr.Next = elem.Next
-				elem.Next.Prev = pr
+	if i.list != l {
+		return
+	}
+	l.unlink(i)
+	i.list = nil
+	l.len--
+}
+
+func (l *list) MoveToFront(i *Item) {
+	if i.list != l || l.head == i {
+		return
+	}
+	l.unlink(i)
+	l.link(i, nil, l.head)
+}
+
+func (l *list) MoveToBack(i *Item) {
+	if i.list != l || l.tail == i {
+		return
+	}
+	l.unlink(i)
+	l.link(i, l.tail, nil)
+}
+
+func (l *list) MoveBefore(i, mark *Item) {
+	if i.list != l || mark.list != l || i == mark || i.Next == mark {
+		return
+	}
+	l.unlink(i)
+	l.link(i, mark.Prev, mark)
+}
+
+func (l *list) MoveAfter(i, mark *Item) {
+	if i.list != l || mark.list != l || i == mark || i.Prev == mark {
+		return
+	}
+	l.unlink(i)
+	l.link(i, mark, mark.Next)
+}
+
+// All iterates over the items from front to back. The current item may be removed.
+func (l *list) All() iter.Seq[*Item] {
+	return func(yield func(*Item) bool) {
+		for i := l.head; i != nil; {
+			next := i.Next
+			if !yield(i) {
+				return
 			}
-			l.len--
-			return
+			i = next
 		}
-		elem = elem.Next
 	}
 }
 
-func (l *list) MoveToFront(i *Item) {
-	elem := l.head
-	for elem != nil {
-		if elem.Value == i.Value {
-			switch {
-			case l.head == elem:
-			case l.tail == elem:
-				l.tail = l.tail.Prev
-				l.tail.Next = nil
-
-				elem.Prev = nil
-				elem.Next = l.head
-				l.head.Prev = elem
-				l.head = elem
-			default:
-				pr := elem.Prev
-				pr.Next = elem.Next
-				elem.Next.Prev = pr
-
-				l.head.Prev = elem
-				elem.Next = l.head
-				l.head = elem
+// Backward iterates over the items from back to front. The current item may be removed.
+func (l *list) Backward() iter.Seq[*Item] {
+	return func(yield func(*Item) bool) {
+		for i := l.tail; i != nil; {
+			prev := i.Prev
+			if !yield(i) {
+				return
 			}
-			return
+			i = prev
 		}
-		elem = elem.Next
 	}
 }
 
+func (l *list) Values() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for i := l.head; i != nil; i = i.Next {
+			if !yield(i.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (l *list) insert(i, prev, next *Item) *Item {
+	i.list = l
+	l.link(i, prev, next)
+	l.len++
+	return i
+}
+
+// link places i between prev and next. nil prev or next means the list front or back.
+func (l *list) link(i, prev, next *Item) {
+	i.Prev = prev
+	i.Next = next
+
+	if prev == nil {
+		l.head = i
+	} else {
+		prev.Next = i
+	}
+
+	if next == nil {
+		l.tail = i
+	} else {
+		next.Prev = i
+	}
+}
+
+func (l *list) unlink(i *Item) {
+	if i.Prev == nil {
+		l.head = i.Next
+	} else {
+		i.Prev.Next = i.Next
+	}
+
+	if i.Next == nil {
+		l.tail = i.Prev
+	} else {
+		i.Next.Prev = i.Prev
+	}
+
+	i.Next = nil
+	i.Prev = nil
+}
+
 func NewList() List {
 	return new(list)
 }
@@ -1,6 +1,7 @@
 package list_test
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -120,4 +121,239 @@ func TestList(t *testing.T) {
 		}
 		require.Equal(t, []int{70, 80, 60, 40, 10, 30, 50}, elems)
 	})
+
+	t.Run("equal values", func(t *testing.T) {
+		l := list.NewList()
+
+		first := l.PushBack(10)  // [10]
+		l.PushBack(20)           // [10, 20]
+		second := l.PushBack(10) // [10, 20, 10]
+
+		l.MoveToFront(second) // [10, 20, 10]
+		require.Same(t, second, l.Front())
+		require.Same(t, first, l.Front().Next)
+
+		l.Remove(first) // [10, 20]
+		require.Equal(t, 2, l.Len())
+		require.Same(t, second, l.Front())
+		require.Equal(t, 20, l.Back().Value)
+		require.Nil(t, l.Back().Next)
+		require.Same(t, second, l.Back().Prev)
+	})
+
+	t.Run("remove all", func(t *testing.T) {
+		l := list.NewList()
+
+		l.PushBack(10) // [10]
+		l.PushBack(20) // [10, 20]
+
+		l.Remove(l.Front()) // [20]
+		require.Nil(t, l.Front().Prev)
+		l.Remove(l.Back()) // []
+
+		require.Equal(t, 0, l.Len())
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+	})
+
+	t.Run("double remove", func(t *testing.T) {
+		l := list.NewList()
+
+		first := l.PushBack(10) // [10]
+		l.PushBack(20)          // [10, 20]
+
+		l.Remove(first) // [20]
+		l.Remove(first) // [20]
+		require.Equal(t, 1, l.Len())
+		require.Equal(t, 20, l.Front().Value)
+		require.Same(t, l.Front(), l.Back())
+	})
+
+	t.Run("move removed item", func(t *testing.T) {
+		l := list.NewList()
+
+		removed := l.PushBack(10) // [10]
+		l.PushBack(20)            // [10, 20]
+		l.PushBack(30)            // [10, 20, 30]
+		l.Remove(removed)         // [20, 30]
+
+		l.MoveToFront(removed)          // [20, 30]
+		l.MoveToBack(removed)           // [20, 30]
+		l.MoveBefore(removed, l.Back()) // [20, 30]
+		l.MoveAfter(removed, l.Front()) // [20, 30]
+		l.MoveAfter(l.Front(), removed) // [20, 30]
+		require.Nil(t, l.InsertBefore(40, removed))
+
+		values := make([]interface{}, 0, l.Len())
+		for v := range l.Values() {
+			values = append(values, v)
+		}
+		require.Equal(t, []interface{}{20, 30}, values)
+		require.Equal(t, 2, l.Len())
+	})
+
+	t.Run("foreign item", func(t *testing.T) {
+		l, other := list.NewList(), list.NewList()
+
+		l.PushBack(10)                // [10]
+		foreign := other.PushBack(20) // [20]
+
+		l.Remove(foreign)
+		l.MoveToFront(foreign)
+		require.Nil(t, l.InsertAfter(30, foreign))
+		require.Equal(t, 1, l.Len())
+		require.Equal(t, 1, other.Len())
+		require.Same(t, foreign, other.Front())
+	})
+}
+
+func TestListIteration(t *testing.T) {
+	l := list.NewList()
+	for _, v := range [...]int{10, 20, 30, 40} {
+		l.PushBack(v)
+	} // [10, 20, 30, 40]
+
+	for i := range l.All() {
+		if i.Value.(int)%20 == 0 {
+			l.Remove(i)
+		}
+	} // [10, 30]
+
+	values := make([]interface{}, 0, l.Len())
+	for v := range l.Values() {
+		values = append(values, v)
+	}
+	require.Equal(t, []interface{}{10, 30}, values)
+
+	for i := range l.Backward() {
+		require.Equal(t, 30, i.Value)
+		break
+	}
+}
+
+func TestTypedList(t *testing.T) {
+	t.Run("empty list", func(t *testing.T) {
+		l := list.NewTypedList[int]()
+
+		require.Equal(t, 0, l.Len())
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+	})
+
+	t.Run("complex", func(t *testing.T) {
+		l := list.NewTypedList[int]()
+
+		l.PushFront(10) // [10]
+		l.PushBack(20)  // [10, 20]
+		l.PushBack(30)  // [10, 20, 30]
+		require.Equal(t, 3, l.Len())
+
+		middle := l.Front().Next // 20
+		l.Remove(middle)         // [10, 30]
+		require.Equal(t, 2, l.Len())
+
+		for i, v := range [...]int{40, 50, 60, 70, 80} {
+			if i%2 == 0 {
+				l.PushFront(v)
+			} else {
+				l.PushBack(v)
+			}
+		} // [80, 60, 40, 10, 30, 50, 70]
+
+		require.Equal(t, 7, l.Len())
+		require.Equal(t, 80, l.Front().Value)
+		require.Equal(t, 70, l.Back().Value)
+
+		l.MoveToFront(l.Front()) // [80, 60, 40, 10, 30, 50, 70]
+		l.MoveToFront(l.Back())  // [70, 80, 60, 40, 10, 30, 50]
+
+		elems := make([]int, 0, l.Len())
+		for i := l.Front(); i != nil; i = i.Next {
+			elems = append(elems, i.Value)
+		}
+		require.Equal(t, []int{70, 80, 60, 40, 10, 30, 50}, elems)
+
+		elems = elems[:0]
+		for i := l.Back(); i != nil; i = i.Prev {
+			elems = append(elems, i.Value)
+		}
+		require.Equal(t, []int{50, 30, 10, 40, 60, 80, 70}, elems)
+	})
+
+	t.Run("remove last", func(t *testing.T) {
+		l := list.NewTypedList[string]()
+
+		l.Remove(l.PushBack("aaa")) // []
+
+		require.Equal(t, 0, l.Len())
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+	})
+
+	t.Run("double remove and move removed item", func(t *testing.T) {
+		l := list.NewTypedList[string]()
+
+		removed := l.PushBack("aaa") // [aaa]
+		l.PushBack("bbb")            // [aaa, bbb]
+		l.Remove(removed)            // [bbb]
+		l.Remove(removed)            // [bbb]
+
+		l.MoveToFront(removed)          // [bbb]
+		l.MoveBefore(removed, l.Back()) // [bbb]
+		require.Nil(t, l.InsertAfter("ccc", removed))
+		require.Equal(t, 1, l.Len())
+		require.Equal(t, "bbb", l.Front().Value)
+		require.Same(t, l.Front(), l.Back())
+	})
+}
+
+var benchSizes = []int{1_000, 1_000_000}
+
+func BenchmarkListMoveToFront(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			l := list.NewList()
+			for i := 0; i < size; i++ {
+				l.PushBack(i)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.MoveToFront(l.Back())
+			}
+		})
+	}
+}
+
+func BenchmarkListRemove(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			l := list.NewList()
+			for i := 0; i < size; i++ {
+				l.PushBack(i)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Remove(l.Back())
+				l.PushBack(i)
+			}
+		})
+	}
+}
+
+func BenchmarkTypedListMoveToFront(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			l := list.NewTypedList[int]()
+			for i := 0; i < size; i++ {
+				l.PushBack(i)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.MoveToFront(l.Back())
+			}
+		})
+	}
 }
@@ -0,0 +1,219 @@
+package list_test
+
+import (
+	"container/list"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+
+	hwlist "github.com/AnnDutova/otus_go_hw/hw04_lru_cache/list"
+)
+
+// adapter exposes a list implementation with items of type I to the model check.
+type adapter[I comparable] struct {
+	len           func() int
+	pushFront     func(v int) I
+	pushBack      func(v int) I
+	foreign       func(v int) I
+	pushBackSelf  func()
+	pushFrontCopy func()
+	insertBefore  func(v int, mark I) I
+	insertAfter   func(v int, mark I) I
+	remove        func(i I)
+	moveToFront   func(i I)
+	moveToBack    func(i I)
+	moveBefore    func(i, mark I)
+	moveAfter     func(i, mark I)
+	forward       func() []int
+	backward      func() []int
+}
+
+func legacyAdapter() adapter[*hwlist.Item] {
+	l, other := hwlist.NewList(), hwlist.NewList()
+	collect := func(items func(func(*hwlist.Item) bool)) []int {
+		values := []int{}
+		for i := range items {
+			values = append(values, i.Value.(int))
+		}
+		return values
+	}
+	return adapter[*hwlist.Item]{
+		len:          l.Len,
+		pushFront:    func(v int) *hwlist.Item { return l.PushFront(v) },
+		pushBack:     func(v int) *hwlist.Item { return l.PushBack(v) },
+		foreign:      func(v int) *hwlist.Item { return other.PushBack(v) },
+		pushBackSelf: func() { l.PushBackList(l) },
+		pushFrontCopy: func() {
+			other := hwlist.NewList()
+			other.PushBackList(l)
+			l.PushFrontList(other)
+		},
+		insertBefore: func(v int, mark *hwlist.Item) *hwlist.Item { return l.InsertBefore(v, mark) },
+		insertAfter:  func(v int, mark *hwlist.Item) *hwlist.Item { return l.InsertAfter(v, mark) },
+		remove:       l.Remove,
+		moveToFront:  l.MoveToFront,
+		moveToBack:   l.MoveToBack,
+		moveBefore:   l.MoveBefore,
+		moveAfter:    l.MoveAfter,
+		forward:      func() []int { return collect(l.All()) },
+		backward:     func() []int { return collect(l.Backward()) },
+	}
+}
+
+func typedAdapter() adapter[*hwlist.TypedItem[int]] {
+	l, other := hwlist.NewTypedList[int](), hwlist.NewTypedList[int]()
+	return adapter[*hwlist.TypedItem[int]]{
+		len:          l.Len,
+		pushFront:    l.PushFront,
+		pushBack:     l.PushBack,
+		foreign:      other.PushBack,
+		pushBackSelf: func() { l.PushBackList(l) },
+		pushFrontCopy: func() {
+			other := hwlist.NewTypedList[int]()
+			other.PushBackList(l)
+			l.PushFrontList(other)
+		},
+		insertBefore: l.InsertBefore,
+		insertAfter:  l.InsertAfter,
+		remove:       l.Remove,
+		moveToFront:  l.MoveToFront,
+		moveToBack:   l.MoveToBack,
+		moveBefore:   l.MoveBefore,
+		moveAfter:    l.MoveAfter,
+		forward:      func() []int { return slices.Collect(l.Values()) },
+		backward: func() []int {
+			values := []int{}
+			for i := range l.Backward() {
+				values = append(values, i.Value)
+			}
+			return values
+		},
+	}
+}
+
+// matchesContainerList applies the same random operations to the list and to container/list
+// and reports whether their contents are equal after every operation. The operations also
+// get removed items and items of another list, which both lists must ignore.
+func matchesContainerList[I comparable](newList func() adapter[I], ops []uint16) bool {
+	l := newList()
+	model, foreignModel := list.New(), list.New()
+	items, stale := []I{}, []I{}
+	elems, staleElems := []*list.Element{}, []*list.Element{}
+	next := 0
+
+	add := func(i I, e *list.Element) bool {
+		var none I
+		if (i == none) != (e == nil) {
+			return false
+		}
+		if e != nil {
+			items = append(items, i)
+			elems = append(elems, e)
+		}
+		return true
+	}
+	value := func() int {
+		next++
+		return next
+	}
+
+	for _, op := range ops {
+		n := int(op >> 4)
+		if len(items) == 0 {
+			v := value()
+			add(l.pushBack(v), model.PushBack(v))
+			continue
+		}
+		// a and b pick from the items in the list followed by the removed and foreign ones.
+		all := slices.Concat(items, stale)
+		allElems := slices.Concat(elems, staleElems)
+		a, b := n%len(all), (n/len(all))%len(all)
+
+		switch op % 14 {
+		case 0:
+			v := value()
+			add(l.pushFront(v), model.PushFront(v))
+		case 1:
+			v := value()
+			add(l.pushBack(v), model.PushBack(v))
+		case 2:
+			v := value()
+			if !add(l.insertBefore(v, all[a]), model.InsertBefore(v, allElems[a])) {
+				return false
+			}
+		case 3:
+			v := value()
+			if !add(l.insertAfter(v, all[a]), model.InsertAfter(v, allElems[a])) {
+				return false
+			}
+		case 4:
+			l.remove(all[a])
+			model.Remove(allElems[a])
+			if a < len(items) {
+				stale = append(stale, items[a])
+				staleElems = append(staleElems, elems[a])
+				items = slices.Delete(items, a, a+1)
+				elems = slices.Delete(elems, a, a+1)
+			}
+		case 5:
+			l.moveToFront(all[a])
+			model.MoveToFront(allElems[a])
+		case 6:
+			l.moveToBack(all[a])
+			model.MoveToBack(allElems[a])
+		case 7, 8:
+			l.moveBefore(all[a], all[b])
+			model.MoveBefore(allElems[a], allElems[b])
+		case 9, 10:
+			l.moveAfter(all[a], all[b])
+			model.MoveAfter(allElems[a], allElems[b])
+		case 11:
+			if l.len() < 64 {
+				l.pushBackSelf()
+				model.PushBackList(model)
+			}
+		case 12:
+			if l.len() < 64 {
+				l.pushFrontCopy()
+				model.PushFrontList(model)
+			}
+		case 13:
+			v := value()
+			stale = append(stale, l.foreign(v))
+			staleElems = append(staleElems, foreignModel.PushBack(v))
+		}
+
+		expected := []int{}
+		for e := model.Front(); e != nil; e = e.Next() {
+			expected = append(expected, e.Value.(int))
+		}
+		if l.len() != model.Len() || !slices.Equal(expected, l.forward()) {
+			return false
+		}
+		slices.Reverse(expected)
+		if !slices.Equal(expected, l.backward()) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestListMatchesContainerList(t *testing.T) {
+	config := &quick.Config{MaxCount: 500}
+
+	t.Run("list", func(t *testing.T) {
+		err := quick.Check(func(ops []uint16) bool {
+			return matchesContainerList(legacyAdapter, ops)
+		}, config)
+		require.NoError(t, err)
+	})
+
+	t.Run("typed list", func(t *testing.T) {
+		err := quick.Check(func(ops []uint16) bool {
+			return matchesContainerList(typedAdapter, ops)
+		}, config)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,213 @@
+package list
+
+import "iter"
+
+type (
+	TypedList[T any] interface {
+		Len() int
+		Front() *TypedItem[T]
+		Back() *TypedItem[T]
+		PushFront(v T) *TypedItem[T]
+		PushBack(v T) *TypedItem[T]
+		PushFrontList(other TypedList[T])
+		PushBackList(other TypedList[T])
+		InsertBefore(v T, mark *TypedItem[T]) *TypedItem[T]
+		InsertAfter(v T, mark *TypedItem[T]) *TypedItem[T]
+		Remove(i *TypedItem[T])
+		MoveToFront(i *TypedItem[T])
+		MoveToBack(i *TypedItem[T])
+		MoveBefore(i, mark *TypedItem[T])
+		MoveAfter(i, mark *TypedItem[T])
+		All() iter.Seq[*TypedItem[T]]
+		Backward() iter.Seq[*TypedItem[T]]
+		Values() iter.Seq[T]
+	}
+
+	TypedItem[T any] struct {
+		Value T
+		Next  *TypedItem[T]
+		Prev  *TypedItem[T]
+
+		list *typedList[T] // The list the item belongs to, nil once removed.
+	}
+
+	typedList[T any] struct {
+		head *TypedItem[T]
+		tail *TypedItem[T]
+		len  int
+	}
+)
+
+func (l *typedList[T]) Len() int {
+	return l.len
+}
+
+func (l *typedList[T]) Front() *TypedItem[T] {
+	return l.head
+}
+
+func (l *typedList[T]) Back() *TypedItem[T] {
+	return l.tail
+}
+
+func (l *typedList[T]) PushFront(v T) *TypedItem[T] {
+	return l.insert(&TypedItem[T]{Value: v}, nil, l.head)
+}
+
+func (l *typedList[T]) PushBack(v T) *TypedItem[T] {
+	return l.insert(&TypedItem[T]{Value: v}, l.tail, nil)
+}
+
+// PushFrontList inserts a copy of the other list at the front. The lists may be the same.
+func (l *typedList[T]) PushFrontList(other TypedList[T]) {
+	for n, i := other.Len(), other.Back(); n > 0; n, i = n-1, i.Prev {
+		l.PushFront(i.Value)
+	}
+}
+
+// PushBackList inserts a copy of the other list at the back. The lists may be the same.
+func (l *typedList[T]) PushBackList(other TypedList[T]) {
+	for n, i := other.Len(), other.Front(); n > 0; n, i = n-1, i.Next {
+		l.PushBack(i.Value)
+	}
+}
+
+// InsertBefore inserts v before mark. It returns nil if mark is not in the list.
+func (l *typedList[T]) InsertBefore(v T, mark *TypedItem[T]) *TypedItem[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&TypedItem[T]{Value: v}, mark.Prev, mark)
+}
+
+// InsertAfter inserts v after mark. It returns nil if mark is not in the list.
+func (l *typedList[T]) InsertAfter(v T, mark *TypedItem[T]) *TypedItem[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&TypedItem[T]{Value: v}, mark, mark.Next)
+}
+
+// Remove removes i from the list. Items that are not in the list, including already removed ones, are ignored.
+func (l *typedList[T]) Remove(i *TypedItem[T]) {
+	if i.list != l {
+		return
+	}
+	l.unlink(i)
+	i.list = nil
+	l.len--
+}
+
+func (l *typedList[T]) MoveToFront(i *TypedItem[T]) {
+	if i.list != l || l.head == i {
+		return
+	}
+	l.unlink(i)
+	l.link(i, nil, l.head)
+}
+
+func (l *typedList[T]) MoveToBack(i *TypedItem[T]) {
+	if i.list != l || l.tail == i {
+		return
+	}
+	l.unlink(i)
+	l.link(i, l.tail, nil)
+}
+
+func (l *typedList[T]) MoveBefore(i, mark *TypedItem[T]) {
+	if i.list != l || mark.list != l || i == mark || i.Next == mark {
+		return
+	}
+	l.unlink(i)
+	l.link(i, mark.Prev, mark)
+}
+
+func (l *typedList[T]) MoveAfter(i, mark *TypedItem[T]) {
+	if i.list != l || mark.list != l || i == mark || i.Prev == mark {
+		return
+	}
+	l.unlink(i)
+	l.link(i, mark, mark.Next)
+}
+
+// All iterates over the items from front to back. The current item may be removed.
+func (l *typedList[T]) All() iter.Seq[*TypedItem[T]] {
+	return func(yield func(*TypedItem[T]) bool) {
+		for i := l.head; i != nil; {
+			next := i.Next
+			if !yield(i) {
+				return
+			}
+			i = next
+		}
+	}
+}
+
+// Backward iterates over the items from back to front. The current item may be removed.
+func (l *typedList[T]) Backward() iter.Seq[*TypedItem[T]] {
+	return func(yield func(*TypedItem[T]) bool) {
+		for i := l.tail; i != nil; {
+			prev := i.Prev
+			if !yield(i) {
+				return
+			}
+			i = prev
+		}
+	}
+}
+
+func (l *typedList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := l.head; i != nil; i = i.Next {
+			if !yield(i.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (l *typedList[T]) insert(i, prev, next *TypedItem[T]) *TypedItem[T] {
+	i.list = l
+	l.link(i, prev, next)
+	l.len++
+	return i
+}
+
+// link places i between prev and next. nil prev or next means the list front or back.
+func (l *typedList[T]) link(i, prev, next *TypedItem[T]) {
+	i.Prev = prev
+	i.Next = next
+
+	if prev == nil {
+		l.head = i
+	} else {
+		prev.Next = i
+	}
+
+	if next == nil {
+		l.tail = i
+	} else {
+		next.Prev = i
+	}
+}
+
+func (l *typedList[T]) unlink(i *TypedItem[T]) {
+	if i.Prev == nil {
+		l.head = i.Next
+	} else {
+		i.Prev.Next = i.Next
+	}
+
+	if i.Next == nil {
+		l.tail = i.Prev
+	} else {
+		i.Next.Prev = i.Prev
+	}
+
+	i.Next = nil
+	i.Prev = nil
+}
+
+func NewTypedList[T any]() TypedList[T] {
+	return new(typedList[T])
+}
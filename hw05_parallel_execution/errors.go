@@ -0,0 +1,70 @@
+package hw05parallelexecution
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskError is an error returned by the task with the given index.
+type TaskError struct {
+	Index int
+	Err   error
+}
+
+func (e TaskError) Error() string {
+	return fmt.Sprintf("task %d: %s", e.Index, e.Err)
+}
+
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is returned for the task which panicked. Stack is the stack trace of the panic,
+// it is not included into the error message.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// RunErrors is returned when Run stops before all tasks are done. Cause is ErrErrorsLimitExceeded
+// or the context error. Both Cause and task errors are reachable with errors.Is and errors.As.
+type RunErrors struct {
+	Cause  error
+	Errors []TaskError
+
+	Completed int
+	Failed    int
+	Skipped   int
+}
+
+func (e *RunErrors) Error() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("%s: %d completed, %d failed, %d skipped",
+		e.Cause, e.Completed, e.Failed, e.Skipped))
+	for _, err := range e.Errors {
+		buf.WriteString("\n")
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+func (e *RunErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	errs = append(errs, e.Cause)
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
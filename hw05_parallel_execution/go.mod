@@ -1,6 +1,6 @@
 module github.com/AnnDutova/otus_go_hw/hw05_parallel_execution
 
-go 1.22
+go 1.23
 
 require (
 	github.com/stretchr/testify v1.7.0
@@ -0,0 +1,106 @@
+package hw05parallelexecution
+
+import "errors"
+
+var ErrInvalidErrorsLimit = errors.New("invalid errors limit")
+
+type limitMode int
+
+const (
+	limitAbsolute limitMode = iota
+	limitIgnore
+	limitRate
+)
+
+// ErrorLimit decides when Run stops because of task errors.
+type ErrorLimit struct {
+	mode   limitMode
+	max    int
+	ratio  float64
+	window int
+}
+
+// IgnoreErrors makes Run execute all tasks whatever they return.
+func IgnoreErrors() ErrorLimit {
+	return ErrorLimit{mode: limitIgnore}
+}
+
+// MaxErrors stops Run after m errors. m must be positive.
+func MaxErrors(m int) ErrorLimit {
+	return ErrorLimit{mode: limitAbsolute, max: m}
+}
+
+// MaxErrorRate stops Run when the share of failed tasks among the last window finished tasks
+// reaches ratio. The limit is not checked until window tasks are finished.
+// ratio must be in (0, 1] and window must be positive.
+func MaxErrorRate(ratio float64, window int) ErrorLimit {
+	return ErrorLimit{mode: limitRate, ratio: ratio, window: window}
+}
+
+// WithErrorLimit replaces the absolute limit m passed to Run.
+func WithErrorLimit(limit ErrorLimit) Option {
+	return func(c *config) {
+		c.limit = &limit
+	}
+}
+
+func (l ErrorLimit) validate() error {
+	switch l.mode {
+	case limitAbsolute:
+		if l.max <= 0 {
+			return ErrInvalidErrorsLimit
+		}
+	case limitRate:
+		if l.ratio <= 0 || l.ratio > 1 || l.window <= 0 {
+			return ErrInvalidErrorsLimit
+		}
+	case limitIgnore:
+	}
+	return nil
+}
+
+func (l ErrorLimit) newCounter() *errorCounter {
+	c := &errorCounter{limit: l}
+	if l.mode == limitRate {
+		c.window = make([]bool, l.window)
+	}
+	return c
+}
+
+// errorCounter tracks results of the finished tasks against the limit.
+type errorCounter struct {
+	limit    ErrorLimit
+	failed   int
+	window   []bool
+	finished int
+	exceeded bool
+}
+
+// add records the task result and reports whether the limit has just been exceeded.
+func (c *errorCounter) add(failed bool) bool {
+	if c.exceeded {
+		return false
+	}
+
+	switch c.limit.mode {
+	case limitAbsolute:
+		if failed {
+			c.failed++
+		}
+		c.exceeded = c.failed >= c.limit.max
+	case limitRate:
+		i := c.finished % len(c.window)
+		if c.window[i] {
+			c.failed--
+		}
+		c.window[i] = failed
+		if failed {
+			c.failed++
+		}
+		c.finished++
+		c.exceeded = c.finished >= len(c.window) &&
+			float64(c.failed) >= c.limit.ratio*float64(len(c.window))
+	case limitIgnore:
+	}
+	return c.exceeded
+}
@@ -0,0 +1,112 @@
+package hw05parallelexecution
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestErrorLimit(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// failing returns count tasks where every task with index i%every == 0 fails.
+	failing := func(count, every int, runTasksCount *int32) []Task {
+		tasks := make([]Task, 0, count)
+		for i := 0; i < count; i++ {
+			tasks = append(tasks, func() error {
+				atomic.AddInt32(runTasksCount, 1)
+				if i%every == 0 {
+					return errors.New("task error")
+				}
+				return nil
+			})
+		}
+		return tasks
+	}
+
+	t.Run("ignore errors", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := failing(50, 1, &runTasksCount)
+
+		err := Run(tasks, 5, 0, WithErrorLimit(IgnoreErrors()))
+
+		require.NoError(t, err)
+		require.Equal(t, int32(50), runTasksCount)
+	})
+
+	t.Run("absolute limit overrides m", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := failing(50, 1, &runTasksCount)
+
+		err := Run(tasks, 1, 100, WithErrorLimit(MaxErrors(3)))
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, runTasksCount, int32(1+3))
+	})
+
+	t.Run("rate below limit", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := failing(100, 4, &runTasksCount)
+
+		err := Run(tasks, 5, 0, WithErrorLimit(MaxErrorRate(0.5, 10)))
+
+		require.NoError(t, err)
+		require.Equal(t, int32(100), runTasksCount)
+	})
+
+	t.Run("rate exceeded", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := failing(100, 2, &runTasksCount)
+
+		err := Run(tasks, 1, 0, WithErrorLimit(MaxErrorRate(0.5, 10)))
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, runTasksCount, int32(10+1))
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		tasks := []Task{
+			func() error {
+				return nil
+			},
+		}
+
+		for _, limit := range []ErrorLimit{
+			MaxErrors(0),
+			MaxErrors(-1),
+			MaxErrorRate(0, 10),
+			MaxErrorRate(1.5, 10),
+			MaxErrorRate(0.5, 0),
+		} {
+			err := Run(tasks, 1, 1, WithErrorLimit(limit))
+			require.Truef(t, errors.Is(err, ErrInvalidErrorsLimit), "actual err - %v", err)
+		}
+
+		err := Run(tasks, 1, -1)
+		require.Truef(t, errors.Is(err, ErrInvalidErrorsLimit), "actual err - %v", err)
+	})
+}
+
+func TestErrorCounter(t *testing.T) {
+	t.Run("window is not filled", func(t *testing.T) {
+		counter := MaxErrorRate(0.5, 4).newCounter()
+
+		for _, failed := range []bool{true, true, true} {
+			require.False(t, counter.add(failed))
+		}
+		require.True(t, counter.add(false)) // [true, true, true, false]
+	})
+
+	t.Run("sliding window", func(t *testing.T) {
+		counter := MaxErrorRate(0.5, 4).newCounter()
+
+		for _, failed := range []bool{true, false, false, false, true, false, false} {
+			require.False(t, counter.add(failed))
+		}
+		require.True(t, counter.add(true)) // [true, false, false, true]
+		require.False(t, counter.add(true))
+	})
+}
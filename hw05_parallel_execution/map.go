@@ -0,0 +1,56 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrTaskSkipped = errors.New("task skipped")
+
+// Result is the outcome of Map for one input.
+type Result[R any] struct {
+	Value R
+	Err   error
+}
+
+// Map calls fn for every input in n goroutines and returns the results in the order of inputs.
+// The limit m and the options work as in Run. The error of fn is kept in the result of its
+// input, the inputs not processed after the run is stopped get ErrTaskSkipped. The returned
+// error is *RunErrors only when the run is stopped. Empty inputs give empty results
+// once n, m and the options are valid.
+func Map[T, R any](
+	ctx context.Context, inputs []T, fn func(ctx context.Context, in T) (R, error), n, m int, opts ...Option,
+) ([]Result[R], error) {
+	if len(inputs) == 0 {
+		if _, _, err := prepare(n, m, opts); err != nil {
+			return nil, err
+		}
+		return []Result[R]{}, nil
+	}
+
+	results := make([]Result[R], len(inputs))
+	tasks := make([]ContextTask, 0, len(inputs))
+	for i, in := range inputs {
+		results[i].Err = ErrTaskSkipped
+		tasks = append(tasks, func(ctx context.Context) error {
+			v, err := fn(ctx, in)
+			results[i] = Result[R]{Value: v, Err: err}
+			return err
+		})
+	}
+
+	runErr, err := runTasks(ctx, tasks, n, m, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A panicked task has not stored its result.
+	for _, taskErr := range runErr.Errors {
+		results[taskErr.Index].Err = taskErr.Err
+	}
+
+	if runErr.Cause == nil {
+		return results, nil
+	}
+	return results, runErr
+}
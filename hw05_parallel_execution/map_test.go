@@ -0,0 +1,124 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMap(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("results in input order", func(t *testing.T) {
+		inputs := make([]int, 100)
+		for i := range inputs {
+			inputs[i] = i
+		}
+
+		results, err := Map(context.Background(), inputs, func(_ context.Context, in int) (string, error) {
+			time.Sleep(time.Millisecond * time.Duration(rand.Intn(10)))
+			return strconv.Itoa(in), nil
+		}, 10, 1)
+
+		require.NoError(t, err)
+		require.Len(t, results, len(inputs))
+		for i, res := range results {
+			require.NoError(t, res.Err)
+			require.Equal(t, strconv.Itoa(i), res.Value)
+		}
+	})
+
+	t.Run("errors per item", func(t *testing.T) {
+		inputs := []int{1, 2, 3, 4, 5, 6}
+
+		results, err := Map(context.Background(), inputs, func(_ context.Context, in int) (int, error) {
+			if in%3 == 0 {
+				return 0, fmt.Errorf("error from %d", in)
+			}
+			return in * in, nil
+		}, 3, 10)
+
+		require.NoError(t, err)
+		for i, res := range results {
+			if inputs[i]%3 == 0 {
+				require.EqualError(t, res.Err, fmt.Sprintf("error from %d", inputs[i]))
+				continue
+			}
+			require.NoError(t, res.Err)
+			require.Equal(t, inputs[i]*inputs[i], res.Value)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		results, err := Map(context.Background(), []int{0, 1}, func(_ context.Context, in int) (int, error) {
+			if in == 0 {
+				panic("boom")
+			}
+			return in, nil
+		}, 2, 10)
+
+		require.NoError(t, err)
+		var panicErr *PanicError
+		require.ErrorAs(t, results[0].Err, &panicErr)
+		require.Equal(t, "boom", panicErr.Value)
+		require.Equal(t, Result[int]{Value: 1}, results[1])
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		workersCount := 5
+		maxErrorsCount := 3
+		inputs := make([]int, 50)
+
+		var runTasksCount int32
+		results, err := Map(context.Background(), inputs, func(context.Context, int) (int, error) {
+			atomic.AddInt32(&runTasksCount, 1)
+			return 0, errors.New("error")
+		}, workersCount, maxErrorsCount)
+
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount+maxErrorsCount))
+
+		var skipped int
+		for _, res := range results {
+			if errors.Is(res.Err, ErrTaskSkipped) {
+				skipped++
+			}
+		}
+		var runErr *RunErrors
+		require.ErrorAs(t, err, &runErr)
+		require.Equal(t, runErr.Skipped, skipped)
+		require.Equal(t, len(inputs)-int(runTasksCount), skipped)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		double := func(_ context.Context, in int) (int, error) {
+			return in * 2, nil
+		}
+
+		_, err := Map(context.Background(), []int{1}, double, 0, 1)
+		require.ErrorIs(t, err, ErrInvalidGoroutinesNumber)
+
+		_, err = Map(context.Background(), nil, double, 0, 1)
+		require.ErrorIs(t, err, ErrInvalidGoroutinesNumber)
+
+		_, err = Map(context.Background(), []int{}, double, 1, 0)
+		require.ErrorIs(t, err, ErrInvalidErrorsLimit)
+	})
+
+	t.Run("empty inputs", func(t *testing.T) {
+		results, err := Map(context.Background(), nil, func(_ context.Context, in int) (int, error) {
+			return in * 2, nil
+		}, 1, 1)
+		require.NoError(t, err)
+		require.NotNil(t, results)
+		require.Empty(t, results)
+	})
+}
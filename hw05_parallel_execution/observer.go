@@ -0,0 +1,66 @@
+package hw05parallelexecution
+
+import (
+	"sync"
+	"time"
+)
+
+// Observer receives the progress of Run. The hooks are never called concurrently, so they may
+// change their state without locking, but they should return quickly. nil hooks are skipped.
+type Observer struct {
+	// OnStart is called when the task starts, retries are not reported.
+	OnStart func(index int)
+	// OnTaskDone is called when the task is done. The duration includes all retries.
+	OnTaskDone func(index int, duration time.Duration, err error)
+	// OnLimitExceeded is called once when the errors limit is exceeded.
+	OnLimitExceeded func()
+}
+
+func WithObserver(observer Observer) Option {
+	return func(c *config) {
+		c.observer = &observer
+	}
+}
+
+// observer serializes the hooks of Observer. The nil observer does nothing.
+type observer struct {
+	m sync.Mutex
+	*Observer
+}
+
+func newObserver(o *Observer) *observer {
+	if o == nil {
+		return nil
+	}
+	return &observer{Observer: o}
+}
+
+func (o *observer) start(index int) {
+	if o == nil || o.OnStart == nil {
+		return
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.OnStart(index)
+}
+
+func (o *observer) done(res taskResult) {
+	if o == nil || o.OnTaskDone == nil {
+		return
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.OnTaskDone(res.Index, res.duration, res.Err)
+}
+
+func (o *observer) limitExceeded() {
+	if o == nil || o.OnLimitExceeded == nil {
+		return
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.OnLimitExceeded()
+}
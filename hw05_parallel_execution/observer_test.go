@@ -0,0 +1,205 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// recorder counts the hooks calls without locking. The hooks are called from the workers,
+// so the violations are recorded and checked by the test after the run.
+type recorder struct {
+	inHook   int32
+	started  map[int]int
+	done     map[int]int
+	errs     map[int]error
+	exceeded int
+
+	m          sync.Mutex
+	violations []string
+}
+
+func newRecorder() *recorder {
+	return &recorder{started: map[int]int{}, done: map[int]int{}, errs: map[int]error{}}
+}
+
+func (r *recorder) violate(format string, args ...any) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.violations = append(r.violations, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) enter() func() {
+	if !atomic.CompareAndSwapInt32(&r.inHook, 0, 1) {
+		r.violate("hooks are called concurrently")
+		return func() {}
+	}
+	time.Sleep(time.Microsecond)
+	return func() {
+		atomic.StoreInt32(&r.inHook, 0)
+	}
+}
+
+// check must be called after the run is finished.
+func (r *recorder) check(t *testing.T) {
+	t.Helper()
+	r.m.Lock()
+	defer r.m.Unlock()
+	require.Empty(t, r.violations)
+}
+
+func (r *recorder) observer() Observer {
+	return Observer{
+		OnStart: func(index int) {
+			defer r.enter()()
+			r.started[index]++
+		},
+		OnTaskDone: func(index int, duration time.Duration, err error) {
+			defer r.enter()()
+			if r.started[index] != 1 {
+				r.violate("task %d is done before start", index)
+			}
+			if duration < 0 {
+				r.violate("task %d has negative duration %s", index, duration)
+			}
+			r.done[index]++
+			r.errs[index] = err
+		},
+		OnLimitExceeded: func() {
+			defer r.enter()()
+			r.exceeded++
+		},
+	}
+}
+
+func TestRunWithObserver(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("hooks are called once per task", func(t *testing.T) {
+		tasksCount := 200
+		tasks := make([]Task, 0, tasksCount)
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func() error {
+				if i%10 == 0 {
+					return errTransient
+				}
+				return nil
+			})
+		}
+		rec := newRecorder()
+
+		err := Run(tasks, 10, 0, WithErrorLimit(IgnoreErrors()), WithObserver(rec.observer()))
+
+		require.NoError(t, err)
+		rec.check(t)
+		require.Len(t, rec.started, tasksCount)
+		require.Len(t, rec.done, tasksCount)
+		for i := 0; i < tasksCount; i++ {
+			require.Equal(t, 1, rec.started[i])
+			require.Equal(t, 1, rec.done[i])
+			if i%10 == 0 {
+				require.ErrorIs(t, rec.errs[i], errTransient)
+			} else {
+				require.NoError(t, rec.errs[i])
+			}
+		}
+		require.Zero(t, rec.exceeded)
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		tasks := make([]Task, 50)
+		for i := range tasks {
+			tasks[i] = func() error {
+				return errTransient
+			}
+		}
+		rec := newRecorder()
+
+		err := Run(tasks, 5, 3, WithObserver(rec.observer()))
+		rec.check(t)
+
+		var runErr *RunErrors
+		require.ErrorAs(t, err, &runErr)
+		require.Equal(t, 1, rec.exceeded)
+		require.Len(t, rec.done, runErr.Completed+runErr.Failed)
+		require.Len(t, rec.started, len(rec.done))
+	})
+
+	t.Run("duration with retries", func(t *testing.T) {
+		clock := newFakeClock()
+		tasks := make([]ContextTask, 0, 3)
+		for i := 0; i < 3; i++ {
+			tasks = append(tasks, func(context.Context) error {
+				clock.Advance(time.Duration(i+1) * time.Second)
+				return nil
+			})
+		}
+
+		var calls int32
+		tasks = append(tasks, func(context.Context) error {
+			clock.Advance(time.Second)
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return errTransient
+			}
+			return nil
+		})
+
+		durations := map[int]time.Duration{}
+		err := RunContext(context.Background(), tasks, 1, 1, WithClock(clock), WithRetry(1, nil),
+			WithObserver(Observer{
+				OnTaskDone: func(index int, duration time.Duration, _ error) {
+					durations[index] = duration
+				},
+			}))
+
+		require.NoError(t, err)
+		require.Equal(t, map[int]time.Duration{
+			0: time.Second,
+			1: 2 * time.Second,
+			2: 3 * time.Second,
+			3: 2 * time.Second,
+		}, durations)
+	})
+
+	t.Run("pool", func(t *testing.T) {
+		rec := newRecorder()
+		pool, err := NewPool(4, 1, WithObserver(rec.observer()))
+		require.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			require.NoError(t, pool.Submit(func(context.Context) error {
+				return nil
+			}))
+		}
+		require.NoError(t, pool.Shutdown(context.Background()))
+		rec.check(t)
+		require.Len(t, rec.started, 100)
+		require.Len(t, rec.done, 100)
+	})
+
+	t.Run("pool limit exceeded", func(t *testing.T) {
+		rec := newRecorder()
+		pool, err := NewPool(2, 2, WithObserver(rec.observer()))
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			err := pool.Submit(func(context.Context) error {
+				return errTransient
+			})
+			if errors.Is(err, ErrErrorsLimitExceeded) {
+				break
+			}
+			require.NoError(t, err)
+		}
+		require.ErrorIs(t, pool.Shutdown(context.Background()), ErrErrorsLimitExceeded)
+		rec.check(t)
+		require.Equal(t, 1, rec.exceeded)
+	})
+}
@@ -0,0 +1,69 @@
+package hw05parallelexecution
+
+type config struct {
+	limit *ErrorLimit
+
+	retries   int
+	backoff   Backoff
+	retryable func(err error) bool
+
+	rate   float64
+	burst  int
+	clock  Clock
+	bucket *tokenBucket
+
+	priority func(index int) int
+	// taskPriority orders the tasks by their own priority without the priority function.
+	taskPriority bool
+
+	observer *Observer
+	hooks    *observer
+}
+
+type Option func(*config)
+
+func (c *config) errorLimit(m int) ErrorLimit {
+	if c.limit != nil {
+		return *c.limit
+	}
+	return MaxErrors(m)
+}
+
+// newQueue returns the queue passing the tasks from the producer to the workers.
+func (c *config) newQueue() taskQueue {
+	if c.priority != nil || c.taskPriority {
+		return newPriorityQueue(c.priority)
+	}
+	return make(channelQueue)
+}
+
+func newConfig(opts []Option) config {
+	c := config{clock: realClock{}}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// prepare validates the arguments shared by all Run functions and returns the config
+// with the errors limit.
+func prepare(n, m int, opts []Option) (config, ErrorLimit, error) {
+	if n <= 0 {
+		return config{}, ErrorLimit{}, ErrInvalidGoroutinesNumber
+	}
+
+	cfg := newConfig(opts)
+	limit := cfg.errorLimit(m)
+	if err := limit.validate(); err != nil {
+		return config{}, ErrorLimit{}, err
+	}
+
+	if cfg.rate != 0 || cfg.burst != 0 {
+		if cfg.rate <= 0 || cfg.burst <= 0 {
+			return config{}, ErrorLimit{}, ErrInvalidRateLimit
+		}
+		cfg.bucket = newTokenBucket(cfg.rate, cfg.burst, cfg.clock)
+	}
+	cfg.hooks = newObserver(cfg.observer)
+	return cfg, limit, nil
+}
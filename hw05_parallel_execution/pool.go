@@ -0,0 +1,264 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrPoolClosed = errors.New("pool is closed")
+
+// Pool runs the submitted tasks in the long-lived workers. The errors limit and the options
+// work as in Run: after the limit is exceeded the pool stops and the queued tasks are skipped.
+// The index of WithPriority is the number of the task in the order of submitting.
+// The queue is unbounded: Submit never blocks, so the caller must not submit tasks faster
+// than the workers run them for long. Shutdown must be called to release the pool.
+type Pool struct {
+	cfg    config
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	tasks  *priorityQueue
+
+	resultCh chan taskResult
+	// done is closed when all workers are stopped and all results are collected.
+	done chan struct{}
+	busy atomic.Int32
+
+	m         sync.Mutex
+	wg        sync.WaitGroup
+	workers   []context.CancelFunc
+	waiters   map[int]chan<- error
+	err       error
+	exceeded  bool
+	submitted int
+	completed int
+	failed    int
+}
+
+// PoolStats is the current state of the pool. Busy is the number of workers running a task.
+type PoolStats struct {
+	Workers   int
+	Busy      int
+	Queued    int
+	Completed int
+	Failed    int
+}
+
+// NewPool starts n workers. The errors limit m and the options work as in Run.
+func NewPool(n, m int, opts ...Option) (*Pool, error) {
+	cfg, limit, err := prepare(n, m, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	p := &Pool{
+		cfg:      cfg,
+		ctx:      ctx,
+		cancel:   cancel,
+		tasks:    newPriorityQueue(cfg.priority),
+		resultCh: make(chan taskResult),
+		done:     make(chan struct{}),
+		waiters:  map[int]chan<- error{},
+	}
+
+	p.m.Lock()
+	for i := 0; i < n; i++ {
+		p.startWorker()
+	}
+	p.m.Unlock()
+
+	go p.collect(limit.newCounter())
+	return p, nil
+}
+
+// Submit queues the task without waiting for it, as the queue is unbounded. It returns
+// ErrPoolClosed after Shutdown and ErrErrorsLimitExceeded after the limit is exceeded.
+func (p *Pool) Submit(task ContextTask) error {
+	return p.submit(task, nil, nil)
+}
+
+// SubmitPriority works like Submit, but the task is queued with the given priority
+// instead of the one from WithPriority. The tasks with higher priority are taken first.
+func (p *Pool) SubmitPriority(task ContextTask, priority int) error {
+	return p.submit(task, &priority, nil)
+}
+
+// SubmitWait queues the task and waits for its result. If ctx is done earlier, the task
+// is not canceled and ctx.Err() is returned. The skipped task returns the reason of the stop.
+func (p *Pool) SubmitWait(ctx context.Context, task ContextTask) error {
+	result := make(chan error, 1)
+	if err := p.submit(task, nil, result); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		select {
+		case err := <-result:
+			return err
+		default:
+			return p.err
+		}
+	}
+}
+
+// submit queues the task with the priority, or with the one from WithPriority if it is nil.
+func (p *Pool) submit(task ContextTask, priority *int, result chan<- error) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
+	index := p.submitted
+	p.submitted++
+	if result != nil {
+		p.waiters[index] = result
+	}
+	t := indexedTask{index: index, task: func(ctx context.Context) error {
+		p.busy.Add(1)
+		defer p.busy.Add(-1)
+		return task(ctx)
+	}}
+	if priority != nil {
+		t.priority = *priority
+	} else {
+		t.priority = p.tasks.priorityOf(index, 0)
+	}
+	p.tasks.push(t)
+	return nil
+}
+
+// Resize changes the number of workers. The removed workers finish their current tasks
+// and do not take the queued ones.
+func (p *Pool) Resize(n int) error {
+	if n <= 0 {
+		return ErrInvalidGoroutinesNumber
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
+	for len(p.workers) < n {
+		p.startWorker()
+	}
+	for _, stop := range p.workers[n:] {
+		stop()
+	}
+	p.workers = p.workers[:n]
+	return nil
+}
+
+func (p *Pool) Stats() PoolStats {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return PoolStats{
+		Workers:   len(p.workers),
+		Busy:      int(p.busy.Load()),
+		Queued:    p.tasks.len(),
+		Completed: p.completed,
+		Failed:    p.failed,
+	}
+}
+
+// Shutdown stops taking new tasks and waits until the queued tasks are done. If ctx is done
+// earlier, the context of the running tasks is canceled with ErrPoolClosed and ctx.Err() is
+// returned. After the errors limit is exceeded, Shutdown returns *RunErrors.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.m.Lock()
+	p.stop(ErrPoolClosed)
+	p.m.Unlock()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		p.cancel(ErrPoolClosed)
+		return ctx.Err()
+	}
+	p.cancel(nil)
+
+	p.m.Lock()
+	p.workers = nil
+	p.m.Unlock()
+
+	if !p.exceeded {
+		return nil
+	}
+	return &RunErrors{
+		Cause:     ErrErrorsLimitExceeded,
+		Completed: p.completed,
+		Failed:    p.failed,
+		Skipped:   p.submitted - p.completed - p.failed,
+	}
+}
+
+// startWorker must be called with p.m locked.
+func (p *Pool) startWorker() {
+	stopCtx, stop := context.WithCancel(p.ctx)
+	p.workers = append(p.workers, stop)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		worker(p.ctx, stopCtx, &p.cfg, p.resultCh, func() (indexedTask, bool) {
+			return p.tasks.next(stopCtx)
+		}, p.tasks.push)
+	}()
+}
+
+// stop closes the queue so the workers exit when it is empty. It must be called with p.m locked.
+func (p *Pool) stop(err error) {
+	if p.err != nil {
+		return
+	}
+	p.err = err
+	p.tasks.close()
+
+	go func() {
+		p.wg.Wait()
+		close(p.resultCh)
+	}()
+}
+
+func (p *Pool) collect(counter *errorCounter) {
+	defer close(p.done)
+
+	for res := range p.resultCh {
+		p.m.Lock()
+		if res.Err == nil {
+			p.completed++
+		} else {
+			p.failed++
+		}
+
+		if waiter, ok := p.waiters[res.Index]; ok {
+			waiter <- res.Err
+			delete(p.waiters, res.Index)
+		}
+
+		exceeded := counter.add(res.Err != nil)
+		if exceeded {
+			p.exceeded = true
+			p.cancel(ErrErrorsLimitExceeded)
+			p.stop(ErrErrorsLimitExceeded)
+		}
+		p.m.Unlock()
+
+		p.cfg.hooks.done(res)
+		if exceeded {
+			p.cfg.hooks.limitExceeded()
+		}
+	}
+}
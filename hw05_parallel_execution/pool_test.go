@@ -0,0 +1,268 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestPool(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("submitted tasks are done before shutdown", func(t *testing.T) {
+		pool, err := NewPool(5, 1)
+		require.NoError(t, err)
+
+		var runTasksCount int32
+		for i := 0; i < 100; i++ {
+			err := pool.Submit(func(context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, pool.Shutdown(context.Background()))
+		require.Equal(t, int32(100), runTasksCount)
+		require.Equal(t, PoolStats{Completed: 100}, pool.Stats())
+		require.ErrorIs(t, pool.Submit(func(context.Context) error { return nil }), ErrPoolClosed)
+	})
+
+	t.Run("submit wait", func(t *testing.T) {
+		pool, err := NewPool(2, 10)
+		require.NoError(t, err)
+		defer pool.Shutdown(context.Background())
+
+		err = pool.SubmitWait(context.Background(), func(context.Context) error {
+			return errTransient
+		})
+		require.ErrorIs(t, err, errTransient)
+
+		err = pool.SubmitWait(context.Background(), func(context.Context) error {
+			panic("boom")
+		})
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+
+		require.NoError(t, pool.SubmitWait(context.Background(), func(context.Context) error {
+			return nil
+		}))
+	})
+
+	t.Run("errors limit stops the pool", func(t *testing.T) {
+		workersCount := 2
+		maxErrorsCount := 3
+		pool, err := NewPool(workersCount, maxErrorsCount)
+		require.NoError(t, err)
+
+		for i := 0; i < maxErrorsCount; i++ {
+			err := pool.SubmitWait(context.Background(), func(context.Context) error {
+				return errTransient
+			})
+			require.ErrorIs(t, err, errTransient)
+		}
+
+		require.Eventually(t, func() bool {
+			return errors.Is(pool.Submit(func(context.Context) error { return nil }), ErrErrorsLimitExceeded)
+		}, time.Second, time.Millisecond)
+		require.ErrorIs(t, pool.Resize(3), ErrErrorsLimitExceeded)
+
+		err = pool.Shutdown(context.Background())
+		var runErr *RunErrors
+		require.ErrorAs(t, err, &runErr)
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+		require.Equal(t, maxErrorsCount, runErr.Failed)
+	})
+
+	t.Run("skipped task after errors limit", func(t *testing.T) {
+		pool, err := NewPool(1, 1)
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		require.NoError(t, pool.Submit(func(context.Context) error {
+			<-release
+			return errTransient
+		}))
+
+		// The worker may take one more task before the pool stops, but not the last one.
+		for i := 0; i < 10; i++ {
+			require.NoError(t, pool.Submit(func(context.Context) error {
+				return nil
+			}))
+		}
+		result := make(chan error)
+		go func() {
+			result <- pool.SubmitWait(context.Background(), func(context.Context) error {
+				return nil
+			})
+		}()
+		require.Eventually(t, func() bool {
+			return pool.Stats().Queued == 11
+		}, time.Second, time.Millisecond)
+		close(release)
+
+		require.ErrorIs(t, <-result, ErrErrorsLimitExceeded)
+		var runErr *RunErrors
+		require.ErrorAs(t, pool.Shutdown(context.Background()), &runErr)
+		require.GreaterOrEqual(t, runErr.Skipped, 10)
+	})
+
+	t.Run("submit priority", func(t *testing.T) {
+		pool, err := NewPool(1, 1)
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		var order []int
+		require.NoError(t, pool.Submit(func(context.Context) error {
+			<-release
+			return nil
+		}))
+		require.Eventually(t, func() bool {
+			return pool.Stats().Busy == 1
+		}, time.Second, time.Millisecond)
+
+		// The only worker is busy, so the tasks are queued and taken by priority.
+		for i, priority := range []int{1, 3, 2, 3} {
+			err := pool.SubmitPriority(func(context.Context) error {
+				order = append(order, i)
+				return nil
+			}, priority)
+			require.NoError(t, err)
+		}
+		close(release)
+
+		require.NoError(t, pool.Shutdown(context.Background()))
+		require.Equal(t, []int{1, 3, 2, 0}, order)
+	})
+
+	t.Run("with priority", func(t *testing.T) {
+		pool, err := NewPool(1, 1, WithPriority(func(index int) int {
+			return index % 2
+		}))
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		var order []int
+		require.NoError(t, pool.Submit(func(context.Context) error {
+			<-release
+			return nil
+		}))
+		require.Eventually(t, func() bool {
+			return pool.Stats().Busy == 1
+		}, time.Second, time.Millisecond)
+
+		for i := 1; i <= 4; i++ {
+			require.NoError(t, pool.Submit(func(context.Context) error {
+				order = append(order, i)
+				return nil
+			}))
+		}
+		require.Equal(t, 4, pool.Stats().Queued)
+		close(release)
+
+		require.NoError(t, pool.Shutdown(context.Background()))
+		require.Equal(t, []int{1, 3, 2, 4}, order)
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		pool, err := NewPool(1, 1)
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		for i := 0; i < 4; i++ {
+			require.NoError(t, pool.Submit(func(context.Context) error {
+				<-release
+				return nil
+			}))
+		}
+
+		require.Eventually(t, func() bool {
+			stats := pool.Stats()
+			return stats.Busy == 1 && stats.Queued == 3
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, pool.Resize(4))
+		require.Eventually(t, func() bool {
+			stats := pool.Stats()
+			return stats.Busy == 4 && stats.Queued == 0
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, pool.Resize(2))
+		require.Equal(t, 2, pool.Stats().Workers)
+		require.ErrorIs(t, pool.Resize(0), ErrInvalidGoroutinesNumber)
+
+		close(release)
+		require.NoError(t, pool.Shutdown(context.Background()))
+		require.Equal(t, 4, pool.Stats().Completed)
+	})
+
+	t.Run("shrink limits running tasks", func(t *testing.T) {
+		pool, err := NewPool(4, 1)
+		require.NoError(t, err)
+
+		// Every value sent to gate lets one running task finish.
+		gate := make(chan struct{})
+		for i := 0; i < 8; i++ {
+			require.NoError(t, pool.Submit(func(context.Context) error {
+				<-gate
+				return nil
+			}))
+		}
+
+		require.Eventually(t, func() bool {
+			stats := pool.Stats()
+			return stats.Busy == 4 && stats.Queued == 4
+		}, time.Second, time.Millisecond)
+		require.NoError(t, pool.Resize(1))
+
+		// The removed workers finish their tasks and do not take the queued ones.
+		for i := 0; i < 4; i++ {
+			gate <- struct{}{}
+		}
+		require.Eventually(t, func() bool {
+			stats := pool.Stats()
+			return stats.Busy == 1 && stats.Queued == 3
+		}, time.Second, time.Millisecond)
+		require.Never(t, func() bool {
+			return pool.Stats().Busy > 1
+		}, 50*time.Millisecond, time.Millisecond)
+
+		for i := 0; i < 4; i++ {
+			gate <- struct{}{}
+		}
+		require.NoError(t, pool.Shutdown(context.Background()))
+		require.Equal(t, 8, pool.Stats().Completed)
+	})
+
+	t.Run("shutdown timeout cancels running tasks", func(t *testing.T) {
+		pool, err := NewPool(1, 1)
+		require.NoError(t, err)
+
+		var cause error
+		require.NoError(t, pool.Submit(func(ctx context.Context) error {
+			<-ctx.Done()
+			cause = context.Cause(ctx)
+			return nil
+		}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+		require.NoError(t, pool.Shutdown(context.Background()))
+		require.ErrorIs(t, cause, ErrPoolClosed)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := NewPool(0, 1)
+		require.ErrorIs(t, err, ErrInvalidGoroutinesNumber)
+
+		_, err = NewPool(1, 0)
+		require.ErrorIs(t, err, ErrInvalidErrorsLimit)
+	})
+}
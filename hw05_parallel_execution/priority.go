@@ -0,0 +1,166 @@
+package hw05parallelexecution
+
+import (
+	"container/heap"
+	"context"
+	"iter"
+	"sync"
+)
+
+// WithPriority makes the workers take the tasks with higher priority first. The index
+// of the task is its position in the tasks list or in the order the tasks were taken
+// from the sequence or channel. Tasks with equal priority are taken in order.
+// The tasks are taken from the sequence or channel without waiting for free workers,
+// so all tasks ready to be taken are queued.
+func WithPriority(priority func(index int) int) Option {
+	return func(c *config) {
+		c.priority = priority
+	}
+}
+
+// PrioritizedTask is the task with its own priority for RunChanPriority.
+type PrioritizedTask struct {
+	Task     ContextTask
+	Priority int
+}
+
+// RunChanPriority works like RunChan, but the workers take the tasks with higher Priority
+// first as with WithPriority. The priority function of WithPriority is not used.
+func RunChanPriority(ctx context.Context, tasks <-chan PrioritizedTask, n, m int, opts ...Option) error {
+	opts = append(opts, func(c *config) {
+		c.priority = nil
+		c.taskPriority = true
+	})
+	return runStream(ctx, chanSource(tasks, func(t PrioritizedTask) PrioritizedTask {
+		return t
+	}), n, m, opts)
+}
+
+// priorityQueue is the unbounded queue of tasks ordered by priority. Without
+// the priority function the tasks are ordered by index.
+type priorityQueue struct {
+	m        sync.Mutex
+	tasks    taskHeap
+	priority func(index int) int
+	closed   bool
+
+	// ready has a value when a waiting worker should check the queue again.
+	ready chan struct{}
+}
+
+func newPriorityQueue(priority func(index int) int) *priorityQueue {
+	return &priorityQueue{
+		priority: priority,
+		ready:    make(chan struct{}, 1),
+	}
+}
+
+func (q *priorityQueue) produce(ctx context.Context, tasks iter.Seq[PrioritizedTask]) int {
+	defer q.close()
+
+	var taken int
+	for task := range tasks {
+		taken++
+		if ctx.Err() != nil {
+			return taken
+		}
+		index := taken - 1
+		q.push(indexedTask{index: index, priority: q.priorityOf(index, task.Priority), task: task.Task})
+	}
+	return taken
+}
+
+// priorityOf returns the priority of the task with the index. The priority function
+// takes precedence over the own priority of the task.
+func (q *priorityQueue) priorityOf(index, priority int) int {
+	if q.priority != nil {
+		return q.priority(index)
+	}
+	return priority
+}
+
+func (q *priorityQueue) push(t indexedTask) {
+	q.m.Lock()
+	heap.Push(&q.tasks, t)
+	q.m.Unlock()
+	q.notify()
+}
+
+func (q *priorityQueue) len() int {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	return len(q.tasks)
+}
+
+func (q *priorityQueue) close() {
+	q.m.Lock()
+	q.closed = true
+	q.m.Unlock()
+	q.notify()
+}
+
+func (q *priorityQueue) next(ctx context.Context) (indexedTask, bool) {
+	for {
+		q.m.Lock()
+		if len(q.tasks) > 0 {
+			t := heap.Pop(&q.tasks).(indexedTask)
+			left := len(q.tasks)
+			q.m.Unlock()
+			if left > 0 {
+				q.notify()
+			}
+			return t, true
+		}
+		closed := q.closed
+		q.m.Unlock()
+
+		if closed {
+			// Wake the next waiting worker to let it stop too.
+			q.notify()
+			return indexedTask{}, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return indexedTask{}, false
+		case <-q.ready:
+		}
+	}
+}
+
+func (q *priorityQueue) notify() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// taskHeap implements heap.Interface with the highest priority on top.
+type taskHeap []indexedTask
+
+func (h taskHeap) Len() int {
+	return len(h)
+}
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].index < h[j].index
+}
+
+func (h taskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *taskHeap) Push(x any) {
+	*h = append(*h, x.(indexedTask))
+}
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	t := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return t
+}
@@ -0,0 +1,125 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunWithPriority(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("higher priority first", func(t *testing.T) {
+		tasksCount := 20
+		queued := make(chan struct{})
+		var order []int
+		mu := sync.Mutex{}
+
+		// The first started task waits until all tasks are queued, the others run in priority order.
+		tasks := func(yield func(ContextTask) bool) {
+			for i := 0; i < tasksCount; i++ {
+				if !yield(func(context.Context) error {
+					<-queued
+					mu.Lock()
+					defer mu.Unlock()
+					order = append(order, i)
+					return nil
+				}) {
+					return
+				}
+			}
+			close(queued)
+		}
+
+		err := RunSeq(context.Background(), tasks, 1, 1, WithPriority(func(index int) int {
+			return index % 3
+		}))
+		require.NoError(t, err)
+
+		require.Len(t, order, tasksCount)
+		rest := order[1:]
+		require.True(t, slices.IsSortedFunc(rest, func(a, b int) int {
+			if a%3 != b%3 {
+				return b%3 - a%3
+			}
+			return a - b
+		}), "actual order - %v", order)
+	})
+
+	t.Run("own priority of channel tasks", func(t *testing.T) {
+		queued := make(chan struct{})
+		var order []int
+		mu := sync.Mutex{}
+		task := func(i int) ContextTask {
+			return func(context.Context) error {
+				<-queued
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, i)
+				return nil
+			}
+		}
+
+		// The first task has the highest priority and waits until the others are queued. The last
+		// one is sent after the previous are taken, so it is the only one which may be not queued in time.
+		tasks := make(chan PrioritizedTask)
+		go func() {
+			defer close(tasks)
+			tasks <- PrioritizedTask{Task: task(0), Priority: 10}
+			for i, priority := range []int{1, 3, 2, 3} {
+				tasks <- PrioritizedTask{Task: task(i + 1), Priority: priority}
+			}
+			tasks <- PrioritizedTask{Task: task(5), Priority: -1}
+			close(queued)
+		}()
+
+		err := RunChanPriority(context.Background(), tasks, 1, 1, WithPriority(func(index int) int {
+			return -index
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 2, 4, 3, 1, 5}, order)
+	})
+
+	t.Run("queue order", func(t *testing.T) {
+		q := newPriorityQueue(func(index int) int {
+			return -index / 2
+		})
+		for i := 0; i < 6; i++ {
+			q.push(indexedTask{index: i, priority: q.priorityOf(i, 0)})
+		}
+		q.close()
+
+		var got []int
+		for {
+			task, ok := q.next(context.Background())
+			if !ok {
+				break
+			}
+			got = append(got, task.index)
+		}
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5}, got)
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		tasks := make([]Task, 50)
+		for i := range tasks {
+			tasks[i] = func() error {
+				return errTransient
+			}
+		}
+
+		err := Run(tasks, 5, 3, WithPriority(func(index int) int {
+			return index
+		}))
+
+		var runErr *RunErrors
+		require.ErrorAs(t, err, &runErr)
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+		require.LessOrEqual(t, runErr.Failed, 5+3)
+		require.Equal(t, 50, runErr.Completed+runErr.Failed+runErr.Skipped)
+	})
+}
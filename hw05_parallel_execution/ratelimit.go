@@ -0,0 +1,106 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrInvalidRateLimit = errors.New("invalid rate limit")
+
+// Clock is the source of time for the rate limit and the retry backoff.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithRateLimit makes all workers together start no more than rate tasks per second.
+// Up to burst tasks may start at once after a pause. Every retry attempt counts as a new start.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *config) {
+		c.rate = rate
+		c.burst = burst
+	}
+}
+
+// WithClock replaces the real time, e.g. with a fake clock in tests.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// acquire waits until the task may start and reports false if ctx is done earlier.
+func (c *config) acquire(ctx context.Context) bool {
+	if c.bucket == nil {
+		return ctx.Err() == nil
+	}
+	return c.bucket.wait(ctx)
+}
+
+// tokenBucket is the token bucket rate limiter. Instead of counting tokens it keeps
+// the time when the bucket gets full, so the delays are exact with a fake clock.
+type tokenBucket struct {
+	m        sync.Mutex
+	clock    Clock
+	interval time.Duration
+	burst    time.Duration
+	full     time.Time
+}
+
+func newTokenBucket(rate float64, burst int, clock Clock) *tokenBucket {
+	interval := time.Duration(float64(time.Second) / rate)
+	return &tokenBucket{
+		clock:    clock,
+		interval: interval,
+		burst:    time.Duration(burst) * interval,
+		full:     clock.Now(),
+	}
+}
+
+// reserve takes a token and returns the delay before it may be used.
+func (b *tokenBucket) reserve() time.Duration {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	now := b.clock.Now()
+	if b.full.Before(now) {
+		b.full = now
+	}
+	b.full = b.full.Add(b.interval)
+	return max(b.full.Sub(now)-b.burst, 0)
+}
+
+// cancel returns the reserved token which was not used.
+func (b *tokenBucket) cancel() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.full = b.full.Add(-b.interval)
+}
+
+func (b *tokenBucket) wait(ctx context.Context) bool {
+	delay := b.reserve()
+	if delay == 0 {
+		return ctx.Err() == nil
+	}
+
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return false
+	case <-b.clock.After(delay):
+		return true
+	}
+}
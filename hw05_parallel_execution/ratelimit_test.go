@@ -0,0 +1,199 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// fakeClock is the Clock which moves only by Advance.
+type fakeClock struct {
+	m      sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.now = c.now.Add(d)
+	timers := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			timers = append(timers, timer)
+			continue
+		}
+		timer.ch <- c.now
+	}
+	c.timers = timers
+}
+
+// Waiters returns the number of timers which have not fired yet.
+func (c *fakeClock) Waiters() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return len(c.timers)
+}
+
+func TestRunWithRateLimit(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	counting := func(count int, runTasksCount *int32) []Task {
+		tasks := make([]Task, 0, count)
+		for i := 0; i < count; i++ {
+			tasks = append(tasks, func() error {
+				atomic.AddInt32(runTasksCount, 1)
+				return nil
+			})
+		}
+		return tasks
+	}
+
+	t.Run("tasks are started at rate", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := counting(6, &runTasksCount)
+		clock := newFakeClock()
+
+		done := make(chan error)
+		go func() {
+			done <- Run(tasks, 4, 1, WithRateLimit(10, 2), WithClock(clock))
+		}()
+
+		// The burst starts at once, the others wait for 100ms each.
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&runTasksCount) == 2 && clock.Waiters() == 4
+		}, time.Second, time.Millisecond)
+		require.Never(t, func() bool {
+			return atomic.LoadInt32(&runTasksCount) > 2
+		}, 50*time.Millisecond, time.Millisecond)
+
+		for i := int32(3); i <= 6; i++ {
+			clock.Advance(100 * time.Millisecond)
+			require.Eventually(t, func() bool {
+				return atomic.LoadInt32(&runTasksCount) == i
+			}, time.Second, time.Millisecond)
+		}
+		require.NoError(t, <-done)
+	})
+
+	t.Run("burst is restored after pause", func(t *testing.T) {
+		clock := newFakeClock()
+		bucket := newTokenBucket(10, 3, clock)
+
+		for i := 0; i < 3; i++ {
+			require.Zero(t, bucket.reserve())
+		}
+		require.Equal(t, 100*time.Millisecond, bucket.reserve())
+		require.Equal(t, 200*time.Millisecond, bucket.reserve())
+
+		clock.Advance(time.Second)
+		for i := 0; i < 3; i++ {
+			require.Zero(t, bucket.reserve())
+		}
+		require.Equal(t, 100*time.Millisecond, bucket.reserve())
+	})
+
+	t.Run("retries are limited", func(t *testing.T) {
+		var calls int32
+		tasks := []Task{
+			func() error {
+				atomic.AddInt32(&calls, 1)
+				return errTransient
+			},
+		}
+		clock := newFakeClock()
+
+		done := make(chan error)
+		go func() {
+			done <- Run(tasks, 1, 1, WithRateLimit(10, 1), WithRetry(2, nil), WithClock(clock))
+		}()
+
+		for i := int32(1); i <= 3; i++ {
+			require.Eventually(t, func() bool {
+				return atomic.LoadInt32(&calls) == i
+			}, time.Second, time.Millisecond)
+			clock.Advance(100 * time.Millisecond)
+		}
+		err := <-done
+		require.Truef(t, errors.Is(err, errTransient), "actual err - %v", err)
+	})
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := counting(10, &runTasksCount)
+		ctxTasks := make([]ContextTask, 0, len(tasks))
+		for _, task := range tasks {
+			ctxTasks = append(ctxTasks, func(context.Context) error {
+				return task()
+			})
+		}
+		clock := newFakeClock()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		done := make(chan error)
+		go func() {
+			done <- RunContext(ctx, ctxTasks, 2, 1, WithRateLimit(1, 1), WithClock(clock))
+		}()
+
+		require.Eventually(t, func() bool {
+			return clock.Waiters() == 2
+		}, time.Second, time.Millisecond)
+		cancel()
+
+		err := <-done
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+		require.Equal(t, int32(1), runTasksCount)
+
+		var runErr *RunErrors
+		require.ErrorAs(t, err, &runErr)
+		require.Equal(t, 9, runErr.Skipped)
+	})
+
+	t.Run("invalid rate limit", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := counting(1, &runTasksCount)
+
+		require.ErrorIs(t, Run(tasks, 1, 1, WithRateLimit(0, 1)), ErrInvalidRateLimit)
+		require.ErrorIs(t, Run(tasks, 1, 1, WithRateLimit(1, 0)), ErrInvalidRateLimit)
+		require.Zero(t, runTasksCount)
+	})
+}
@@ -0,0 +1,82 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the delay before the given retry attempt, starting with 1.
+type Backoff func(attempt int) time.Duration
+
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay on every attempt up to maxDelay. The delay is randomly
+// changed by up to jitter part of it, e.g. 0.2 gives the delay in [0.8*d, 1.2*d].
+func ExponentialBackoff(base, maxDelay time.Duration, jitter float64) Backoff {
+	return func(attempt int) time.Duration {
+		delay := maxDelay
+		if attempt < 63 && base < maxDelay>>(attempt-1) {
+			delay = base << (attempt - 1)
+		}
+		if jitter > 0 {
+			delay += time.Duration(float64(delay) * jitter * (2*rand.Float64() - 1))
+		}
+		return delay
+	}
+}
+
+// WithRetry makes a failed task run again up to retries times waiting for backoff between
+// the attempts. Only the last error counts toward the errors limit. nil backoff means no delay.
+func WithRetry(retries int, backoff Backoff) Option {
+	return func(c *config) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// WithRetryable sets the predicate deciding which errors are retried. By default all errors are.
+func WithRetryable(retryable func(err error) bool) Option {
+	return func(c *config) {
+		c.retryable = retryable
+	}
+}
+
+func (c *config) executeWithRetry(ctx context.Context, task ContextTask) error {
+	err := execute(ctx, task)
+	for attempt := 1; attempt <= c.retries && err != nil; attempt++ {
+		if c.retryable != nil && !c.retryable(err) {
+			return err
+		}
+		if !c.sleep(ctx, c.delay(attempt)) || !c.acquire(ctx) {
+			return err
+		}
+		err = execute(ctx, task)
+	}
+	return err
+}
+
+func (c *config) delay(attempt int) time.Duration {
+	if c.backoff == nil {
+		return 0
+	}
+	return c.backoff(attempt)
+}
+
+// sleep waits for the delay and reports false if ctx is done earlier.
+func (c *config) sleep(ctx context.Context, delay time.Duration) bool {
+	if delay <= 0 {
+		return ctx.Err() == nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-c.clock.After(delay):
+		return true
+	}
+}
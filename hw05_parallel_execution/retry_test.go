@@ -0,0 +1,110 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+var errTransient = errors.New("transient error")
+
+func TestRunWithRetry(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	flaky := func(failures int32, calls *int32) Task {
+		return func() error {
+			if atomic.AddInt32(calls, 1) <= failures {
+				return errTransient
+			}
+			return nil
+		}
+	}
+
+	t.Run("succeeds after retries", func(t *testing.T) {
+		var calls int32
+		tasks := []Task{flaky(2, &calls)}
+
+		err := Run(tasks, 1, 1, WithRetry(2, ConstantBackoff(time.Millisecond)))
+
+		require.NoError(t, err)
+		require.Equal(t, int32(3), calls)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		var calls int32
+		tasks := []Task{flaky(3, &calls)}
+
+		err := Run(tasks, 1, 1, WithRetry(2, nil))
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.Truef(t, errors.Is(err, errTransient), "actual err - %v", err)
+		require.Equal(t, int32(3), calls)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		var calls int32
+		tasks := []Task{flaky(3, &calls)}
+
+		err := Run(tasks, 1, 1, WithRetry(2, nil), WithRetryable(func(err error) bool {
+			return !errors.Is(err, errTransient)
+		}))
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.Equal(t, int32(1), calls)
+	})
+
+	t.Run("context done during backoff", func(t *testing.T) {
+		var calls int32
+		tasks := []ContextTask{
+			func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return errTransient
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := RunContext(ctx, tasks, 1, 2, WithRetry(5, ConstantBackoff(time.Second)))
+
+		require.Truef(t, errors.Is(err, context.DeadlineExceeded), "actual err - %v", err)
+		require.Truef(t, errors.Is(err, errTransient), "actual err - %v", err)
+		require.Less(t, time.Since(start), time.Second)
+		require.Equal(t, int32(1), calls)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("constant", func(t *testing.T) {
+		backoff := ConstantBackoff(time.Second)
+
+		require.Equal(t, time.Second, backoff(1))
+		require.Equal(t, time.Second, backoff(10))
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		backoff := ExponentialBackoff(10*time.Millisecond, time.Second, 0)
+
+		require.Equal(t, 10*time.Millisecond, backoff(1))
+		require.Equal(t, 20*time.Millisecond, backoff(2))
+		require.Equal(t, 80*time.Millisecond, backoff(4))
+		require.Equal(t, time.Second, backoff(8))
+		require.Equal(t, time.Second, backoff(100))
+	})
+
+	t.Run("exponential with jitter", func(t *testing.T) {
+		backoff := ExponentialBackoff(100*time.Millisecond, time.Second, 0.5)
+
+		for i := 0; i < 100; i++ {
+			delay := backoff(2)
+			require.GreaterOrEqual(t, delay, 100*time.Millisecond)
+			require.LessOrEqual(t, delay, 300*time.Millisecond)
+		}
+	})
+}
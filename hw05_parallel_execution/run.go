@@ -1,8 +1,13 @@
 package hw05parallelexecution
 
 import (
+	"context"
 	"errors"
+	"iter"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"time"
 )
 
 var (
@@ -11,69 +16,268 @@ var (
 	ErrEmptyTasksList          = errors.New("empty tasks list")
 )
 
+// maxStreamErrors is the number of the last task errors kept by RunSeq and RunChan,
+// so the endless stream of failing tasks does not grow RunErrors.Errors without bound.
+const maxStreamErrors = 1000
+
 type Task func() error
 
+// ContextTask receives the context of RunContext. The context is also canceled
+// when the errors limit is exceeded, so long tasks may stop early.
+type ContextTask func(ctx context.Context) error
+
 // Run starts tasks in n goroutines and stops its work when receiving m errors from tasks.
-func Run(tasks []Task, n, m int) error {
+// m must be positive, other modes are set with WithErrorLimit.
+func Run(tasks []Task, n, m int, opts ...Option) error {
+	ctxTasks := make([]ContextTask, 0, len(tasks))
+	for _, task := range tasks {
+		ctxTasks = append(ctxTasks, func(context.Context) error {
+			return task()
+		})
+	}
+	return RunContext(context.Background(), ctxTasks, n, m, opts...)
+}
+
+// RunContext works like Run, but also stops taking new tasks as soon as ctx is done.
+// When stopped, it returns *RunErrors with ctx.Err() or ErrErrorsLimitExceeded as the cause.
+func RunContext(ctx context.Context, tasks []ContextTask, n, m int, opts ...Option) error {
+	runErr, err := runTasks(ctx, tasks, n, m, opts)
+	if err != nil {
+		return err
+	}
+	if runErr.Cause == nil {
+		return nil
+	}
+	return runErr
+}
+
+// runTasks validates the arguments and runs the tasks. Unlike RunContext, it returns
+// the task errors even if the run is not stopped.
+func runTasks(ctx context.Context, tasks []ContextTask, n, m int, opts []Option) (*RunErrors, error) {
 	if len(tasks) == 0 {
-		return ErrEmptyTasksList
+		return nil, ErrEmptyTasksList
+	}
+
+	cfg, limit, err := prepare(n, m, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	runErr := run(ctx, seqSource(slices.Values(tasks)), n, limit, cfg, 0)
+	runErr.Skipped = len(tasks) - runErr.Completed - runErr.Failed
+	return runErr, nil
+}
+
+// RunSeq works like RunContext, but takes tasks from the sequence one by one, so the memory
+// does not depend on the number of tasks. The sequence is not iterated further after
+// RunSeq stops, but RunSeq waits for the sequence to yield the next task or to end, so it
+// must not block for long. Skipped counts only the tasks already taken from the sequence.
+// RunErrors.Errors keeps only the last 1000 task errors, Failed counts all of them.
+func RunSeq(ctx context.Context, tasks iter.Seq[ContextTask], n, m int, opts ...Option) error {
+	return runStream(ctx, seqSource(tasks), n, m, opts)
+}
+
+// RunChan works like RunSeq taking tasks from the channel until it is closed.
+// Unlike RunSeq, it does not wait for the next task after stopping.
+func RunChan(ctx context.Context, tasks <-chan ContextTask, n, m int, opts ...Option) error {
+	return runStream(ctx, chanSource(tasks, withoutPriority), n, m, opts)
+}
+
+func runStream(ctx context.Context, source taskSource, n, m int, opts []Option) error {
+	cfg, limit, err := prepare(n, m, opts)
+	if err != nil {
+		return err
 	}
 
-	if n <= 0 {
-		return ErrInvalidGoroutinesNumber
+	runErr := run(ctx, source, n, limit, cfg, maxStreamErrors)
+	if runErr.Cause == nil {
+		return nil
+	}
+	return runErr
+}
+
+// taskSource returns the sequence of tasks which stops when ctx is done.
+type taskSource func(ctx context.Context) iter.Seq[PrioritizedTask]
+
+func seqSource(tasks iter.Seq[ContextTask]) taskSource {
+	return func(context.Context) iter.Seq[PrioritizedTask] {
+		return func(yield func(PrioritizedTask) bool) {
+			for task := range tasks {
+				if !yield(withoutPriority(task)) {
+					return
+				}
+			}
+		}
 	}
+}
+
+// chanSource takes the tasks from the channel converting them with prioritize.
+func chanSource[T any](tasks <-chan T, prioritize func(T) PrioritizedTask) taskSource {
+	return func(ctx context.Context) iter.Seq[PrioritizedTask] {
+		return func(yield func(PrioritizedTask) bool) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case task, ok := <-tasks:
+					if !ok || !yield(prioritize(task)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func withoutPriority(task ContextTask) PrioritizedTask {
+	return PrioritizedTask{Task: task}
+}
 
-	errCh := make(chan error)
-	taskCh := make(chan Task, len(tasks))
-	signalCh := make(chan struct{})
+// run executes the tasks from the source. If keep is positive, only the last keep task errors
+// are collected.
+func run(ctx context.Context, source taskSource, n int, limit ErrorLimit, cfg config, keep int) *RunErrors {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	resultCh := make(chan taskResult)
+	tasks := cfg.newQueue()
 
 	wg := sync.WaitGroup{}
 	for i := 0; i < n; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			worker(errCh, signalCh, taskCh)
+			worker(runCtx, runCtx, &cfg, resultCh, func() (indexedTask, bool) {
+				return tasks.next(runCtx)
+			}, nil)
 		}()
 	}
 
+	var taken int
+	producerDone := make(chan struct{})
 	go func() {
-		defer close(errCh)
+		defer close(producerDone)
+		taken = tasks.produce(runCtx, source(runCtx))
+	}()
+
+	go func() {
+		defer close(resultCh)
 		wg.Wait()
 	}()
 
-	for _, task := range tasks {
-		taskCh <- task
-	}
-	close(taskCh)
-
-	var count int
-	for {
-		_, ok := <-errCh
-		if ok {
-			count++
-			if count == m {
-				close(signalCh)
-			}
+	runErr := &RunErrors{}
+	counter := limit.newCounter()
+	for res := range resultCh {
+		if res.Err == nil {
+			runErr.Completed++
 		} else {
-			break
+			runErr.Failed++
+			if keep > 0 && len(runErr.Errors) == keep {
+				runErr.Errors = runErr.Errors[1:]
+			}
+			runErr.Errors = append(runErr.Errors, res.TaskError)
+		}
+
+		cfg.hooks.done(res)
+		if counter.add(res.Err != nil) {
+			cancel(ErrErrorsLimitExceeded)
+			cfg.hooks.limitExceeded()
 		}
 	}
+	cancel(nil)
+	<-producerDone
+
+	runErr.Skipped = taken - runErr.Completed - runErr.Failed
+	slices.SortFunc(runErr.Errors, func(a, b TaskError) int {
+		return a.Index - b.Index
+	})
 
-	if count >= m {
-		return ErrErrorsLimitExceeded
+	switch {
+	case counter.exceeded:
+		runErr.Cause = ErrErrorsLimitExceeded
+	case ctx.Err() != nil:
+		runErr.Cause = ctx.Err()
 	}
-	return nil
+	return runErr
+}
+
+// taskQueue passes the tasks from the producer to the workers.
+type taskQueue interface {
+	// produce puts tasks into the queue until the sequence ends or ctx is done, closes
+	// the queue and returns the number of tasks taken from the sequence.
+	produce(ctx context.Context, tasks iter.Seq[PrioritizedTask]) int
+	// next returns the task for a worker or false if the queue is closed or ctx is done.
+	next(ctx context.Context) (indexedTask, bool)
 }
 
-func worker(errCh chan error, signalCh <-chan struct{}, taskCh <-chan Task) {
-	for task := range taskCh {
+// channelQueue hands the tasks to the workers one by one in order.
+type channelQueue chan indexedTask
+
+func (q channelQueue) produce(ctx context.Context, tasks iter.Seq[PrioritizedTask]) int {
+	defer close(q)
+
+	var taken int
+	for task := range tasks {
+		taken++
 		select {
-		case <-signalCh:
+		case <-ctx.Done():
+			return taken
+		case q <- indexedTask{index: taken - 1, task: task.Task}:
+		}
+	}
+	return taken
+}
+
+func (q channelQueue) next(context.Context) (indexedTask, bool) {
+	t, ok := <-q
+	return t, ok
+}
+
+type indexedTask struct {
+	index    int
+	priority int
+	task     ContextTask
+}
+
+type taskResult struct {
+	TaskError
+	duration time.Duration
+}
+
+// worker runs the tasks returned by next with ctx until next returns false or stop is done.
+// The task taken after stop is done is given back to requeue if it is not nil and ctx is not done.
+func worker(
+	ctx, stop context.Context, cfg *config, resultCh chan<- taskResult,
+	next func() (indexedTask, bool), requeue func(indexedTask),
+) {
+	for stop.Err() == nil {
+		t, ok := next()
+		if !ok {
 			return
-		default:
-			if err := task(); err != nil {
-				errCh <- err
+		}
+		if stop.Err() != nil || !cfg.acquire(stop) {
+			if requeue != nil && ctx.Err() == nil {
+				requeue(t)
 			}
+			return
+		}
+
+		start := cfg.clock.Now()
+		cfg.hooks.start(t.index)
+		err := cfg.executeWithRetry(ctx, t.task)
+		resultCh <- taskResult{
+			TaskError: TaskError{Index: t.index, Err: err},
+			duration:  cfg.clock.Now().Sub(start),
 		}
 	}
 }
+
+// execute runs the task converting its panic into *PanicError.
+func execute(ctx context.Context, task ContextTask) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return task(ctx)
+}
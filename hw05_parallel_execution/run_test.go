@@ -1,6 +1,7 @@
 package hw05parallelexecution
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -41,8 +42,10 @@ func TestRun(t *testing.T) {
 	})
 
 	t.Run("Max zero errors", func(t *testing.T) {
+		var runTasksCount int32
 		tasks := []Task{
 			func() error {
+				atomic.AddInt32(&runTasksCount, 1)
 				return nil
 			},
 		}
@@ -52,7 +55,8 @@ func TestRun(t *testing.T) {
 		err := Run(tasks, workersCount, maxErrorsCount)
 
 		require.NotNil(t, err)
-		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.Truef(t, errors.Is(err, ErrInvalidErrorsLimit), "actual err - %v", err)
+		require.Zero(t, runTasksCount)
 	})
 
 	t.Run("if were errors in first M tasks, than finished not more N+M tasks", func(t *testing.T) {
@@ -128,3 +132,217 @@ func TestRun(t *testing.T) {
 		require.Equal(t, runTasksCount, int32(tasksCount), "not all tasks were completed")
 	})
 }
+
+func TestRunContext(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("canceled context", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]ContextTask, 0, tasksCount)
+
+		var runTasksCount int32
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) error {
+				if atomic.AddInt32(&runTasksCount, 1) == 10 {
+					cancel()
+				}
+				return nil
+			})
+		}
+
+		workersCount := 5
+		err := RunContext(ctx, tasks, workersCount, 1)
+
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+		require.False(t, errors.Is(err, ErrErrorsLimitExceeded))
+		require.LessOrEqual(t, runTasksCount, int32(10+workersCount), "extra tasks were started")
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]ContextTask, 0, tasksCount)
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Second):
+				}
+				return nil
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := RunContext(ctx, tasks, 5, 1)
+
+		require.Truef(t, errors.Is(err, context.DeadlineExceeded), "actual err - %v", err)
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("limit cancels running tasks", func(t *testing.T) {
+		errTask := errors.New("task error")
+		var canceledCount int32
+		started := make(chan struct{})
+		tasks := []ContextTask{
+			func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				atomic.AddInt32(&canceledCount, 1)
+				return nil
+			},
+			func(context.Context) error {
+				<-started
+				return errTask
+			},
+		}
+
+		err := RunContext(context.Background(), tasks, 2, 1)
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.False(t, errors.Is(err, context.Canceled))
+		require.Equal(t, int32(1), canceledCount)
+	})
+
+	t.Run("tasks without errors", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]ContextTask, 0, tasksCount)
+
+		var runTasksCount int32
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func(context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			})
+		}
+
+		err := RunContext(context.Background(), tasks, 5, 1)
+
+		require.NoError(t, err)
+		require.Equal(t, int32(tasksCount), runTasksCount)
+	})
+}
+
+func TestRunErrors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]Task, 0, tasksCount)
+		errTask := errors.New("task error")
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func() error {
+				if i%2 == 0 {
+					return fmt.Errorf("task %d: %w", i, errTask)
+				}
+				return nil
+			})
+		}
+
+		workersCount := 4
+		maxErrorsCount := 5
+		err := Run(tasks, workersCount, maxErrorsCount)
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.Truef(t, errors.Is(err, errTask), "actual err - %v", err)
+
+		var runErr *RunErrors
+		require.True(t, errors.As(err, &runErr))
+		require.GreaterOrEqual(t, runErr.Failed, maxErrorsCount)
+		require.Len(t, runErr.Errors, runErr.Failed)
+		require.Equal(t, tasksCount, runErr.Completed+runErr.Failed+runErr.Skipped)
+		require.Positive(t, runErr.Skipped)
+		for i, taskErr := range runErr.Errors {
+			require.Zero(t, taskErr.Index%2)
+			if i > 0 {
+				require.Greater(t, taskErr.Index, runErr.Errors[i-1].Index)
+			}
+		}
+
+		var taskErr TaskError
+		require.True(t, errors.As(err, &taskErr))
+		require.Equal(t, runErr.Errors[0], taskErr)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tasks := []ContextTask{
+			func(context.Context) error {
+				return nil
+			},
+		}
+		err := RunContext(ctx, tasks, 1, 1)
+
+		require.Truef(t, errors.Is(err, context.Canceled), "actual err - %v", err)
+
+		var runErr *RunErrors
+		require.True(t, errors.As(err, &runErr))
+		require.Equal(t, RunErrors{Cause: context.Canceled, Skipped: 1}, *runErr)
+	})
+
+	t.Run("errors below limit", func(t *testing.T) {
+		tasks := []Task{
+			func() error {
+				return errors.New("task error")
+			},
+			func() error {
+				return nil
+			},
+		}
+
+		err := Run(tasks, 2, 2)
+		require.NoError(t, err)
+	})
+}
+
+func TestRunPanics(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("panic counts as error", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := []Task{
+			func() error {
+				panic("boom")
+			},
+			func() error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			},
+		}
+
+		err := Run(tasks, 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, int32(1), runTasksCount)
+
+		err = Run(tasks, 1, 1)
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(err, &panicErr))
+		require.Equal(t, "boom", panicErr.Value)
+		require.Contains(t, string(panicErr.Stack), "TestRunPanics")
+		require.Equal(t, "task panicked: boom", panicErr.Error())
+	})
+
+	t.Run("panic with error", func(t *testing.T) {
+		errTask := errors.New("task error")
+		tasks := []Task{
+			func() error {
+				panic(errTask)
+			},
+		}
+
+		err := Run(tasks, 1, 1)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(err, &panicErr))
+		require.Truef(t, errors.Is(err, errTask), "actual err - %v", err)
+	})
+}
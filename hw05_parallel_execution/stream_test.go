@@ -0,0 +1,151 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// generate yields count tasks created by newTask and counts how many were taken.
+func generate(count int, taken *int32, newTask func(i int) ContextTask) func(yield func(ContextTask) bool) {
+	return func(yield func(ContextTask) bool) {
+		for i := 0; i < count; i++ {
+			atomic.AddInt32(taken, 1)
+			if !yield(newTask(i)) {
+				return
+			}
+		}
+	}
+}
+
+func TestRunSeq(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("tasks without errors", func(t *testing.T) {
+		var taken, runTasksCount int32
+		tasks := generate(10_000, &taken, func(int) ContextTask {
+			return func(context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			}
+		})
+
+		err := RunSeq(context.Background(), tasks, 10, 1)
+
+		require.NoError(t, err)
+		require.Equal(t, int32(10_000), runTasksCount)
+	})
+
+	t.Run("empty sequence", func(t *testing.T) {
+		var taken int32
+		tasks := generate(0, &taken, nil)
+
+		err := RunSeq(context.Background(), tasks, 10, 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("producer stops on errors limit", func(t *testing.T) {
+		var taken, runTasksCount int32
+		tasks := generate(1_000_000, &taken, func(i int) ContextTask {
+			return func(context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				if i%2 == 0 {
+					return errors.New("task error")
+				}
+				return nil
+			}
+		})
+
+		workersCount := 5
+		maxErrorsCount := 10
+		err := RunSeq(context.Background(), tasks, workersCount, maxErrorsCount)
+
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+		require.LessOrEqual(t, taken, int32(3*(workersCount+maxErrorsCount)), "producer was not stopped")
+
+		var runErr *RunErrors
+		require.True(t, errors.As(err, &runErr))
+		require.Equal(t, int(taken), runErr.Completed+runErr.Failed+runErr.Skipped)
+		require.Equal(t, int(runTasksCount), runErr.Completed+runErr.Failed)
+	})
+
+	t.Run("keeps only the last errors", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tasksCount := maxStreamErrors + 100
+		var taken int32
+		tasks := func(yield func(ContextTask) bool) {
+			generate(tasksCount, &taken, func(int) ContextTask {
+				return func(context.Context) error {
+					return errors.New("task error")
+				}
+			})(yield)
+			cancel()
+		}
+
+		workersCount := 5
+		err := RunSeq(ctx, tasks, workersCount, 0, WithErrorLimit(IgnoreErrors()))
+
+		var runErr *RunErrors
+		require.ErrorAs(t, err, &runErr)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, tasksCount, runErr.Failed+runErr.Skipped)
+		require.GreaterOrEqual(t, runErr.Failed, tasksCount-workersCount)
+		require.Len(t, runErr.Errors, maxStreamErrors)
+	})
+
+	t.Run("invalid workers count", func(t *testing.T) {
+		var taken int32
+		err := RunSeq(context.Background(), generate(1, &taken, nil), 0, 1)
+
+		require.Truef(t, errors.Is(err, ErrInvalidGoroutinesNumber), "actual err - %v", err)
+		require.Zero(t, taken)
+	})
+}
+
+func TestRunChan(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("reads until closed", func(t *testing.T) {
+		var runTasksCount int32
+		tasks := make(chan ContextTask)
+		go func() {
+			defer close(tasks)
+			for i := 0; i < 100; i++ {
+				tasks <- func(context.Context) error {
+					atomic.AddInt32(&runTasksCount, 1)
+					return nil
+				}
+			}
+		}()
+
+		err := RunChan(context.Background(), tasks, 5, 1)
+
+		require.NoError(t, err)
+		require.Equal(t, int32(100), runTasksCount)
+	})
+
+	t.Run("open channel and errors limit", func(t *testing.T) {
+		tasks := make(chan ContextTask, 1)
+		tasks <- func(context.Context) error {
+			return errors.New("task error")
+		}
+
+		err := RunChan(context.Background(), tasks, 5, 1)
+		require.Truef(t, errors.Is(err, ErrErrorsLimitExceeded), "actual err - %v", err)
+	})
+
+	t.Run("open channel and context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := RunChan(ctx, make(chan ContextTask), 5, 1)
+		require.Truef(t, errors.Is(err, context.DeadlineExceeded), "actual err - %v", err)
+	})
+}
@@ -0,0 +1,25 @@
+package pipeline
+
+import "context"
+
+// ContextStage is the Stage which receives the context of ExecutePipelineContext.
+// The stage should stop the work in progress when ctx is done.
+type ContextStage func(ctx context.Context, in In) (out Out)
+
+// ExecutePipelineContext works like ExecutePipeline, but the stages are stopped when ctx is done.
+// The reason of the stop is available to the stages with context.Cause.
+func ExecutePipelineContext(ctx context.Context, in In, stages ...ContextStage) Out {
+	if len(stages) == 0 {
+		return in
+	}
+
+	if in == nil {
+		return in
+	}
+
+	for _, stage := range stages {
+		in = typedExecutor(ctx.Done(), stage(ctx, in))
+	}
+
+	return in
+}
@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errAborted = errors.New("aborted")
+
+func TestPipelineContext(t *testing.T) {
+	wg := sync.WaitGroup{}
+	causes := make(chan error, 10)
+
+	// Stage generator, the stage stops sleeping when ctx is done and reports the cause.
+	g := func(_ string, f func(v interface{}) interface{}) ContextStage {
+		return func(ctx context.Context, in In) Out {
+			out := make(Bi)
+			wg.Add(1)
+			go func() {
+				defer func() {
+					wg.Done()
+					close(out)
+				}()
+				for v := range in {
+					select {
+					case <-ctx.Done():
+						causes <- context.Cause(ctx)
+						return
+					case <-time.After(sleepPerStage):
+					}
+					out <- f(v)
+				}
+			}()
+			return out
+		}
+	}
+
+	stages := []ContextStage{
+		g("Dummy", func(v interface{}) interface{} { return v }),
+		g("Multiplier (* 2)", func(v interface{}) interface{} { return v.(int) * 2 }),
+		g("Adder (+ 100)", func(v interface{}) interface{} { return v.(int) + 100 }),
+		g("Stringifier", func(v interface{}) interface{} { return strconv.Itoa(v.(int)) }),
+	}
+
+	send := func(in Bi, data []int) {
+		for _, v := range data {
+			in <- v
+		}
+		close(in)
+	}
+
+	t.Run("empty stages", func(t *testing.T) {
+		in := make(Bi)
+		go send(in, []int{1, 2, 3, 4, 5})
+
+		result := make([]int, 0, 5)
+		for s := range ExecutePipelineContext(context.Background(), in) {
+			result = append(result, s.(int))
+		}
+
+		require.Equal(t, []int{1, 2, 3, 4, 5}, result)
+	})
+
+	t.Run("nil in channel", func(t *testing.T) {
+		require.Nil(t, ExecutePipelineContext(context.Background(), nil, stages...))
+	})
+
+	t.Run("simple case", func(t *testing.T) {
+		in := make(Bi)
+		data := []int{1, 2, 3, 4, 5}
+		go send(in, data)
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range ExecutePipelineContext(context.Background(), in, stages...) {
+			result = append(result, s.(string))
+		}
+		elapsed := time.Since(start)
+		wg.Wait()
+
+		require.Equal(t, []string{"102", "104", "106", "108", "110"}, result)
+		require.Less(t,
+			int64(elapsed),
+			// ~0.8s for processing 5 values in 4 stages (100ms every) concurrently
+			int64(sleepPerStage)*int64(len(stages)+len(data)-1)+int64(fault))
+	})
+
+	t.Run("cancel with cause", func(t *testing.T) {
+		// The stopped stages do not read all values.
+		data := []int{1, 2, 3, 4, 5}
+		in := make(Bi, len(data))
+		ctx, cancel := context.WithCancelCause(context.Background())
+
+		// Abort in the middle of the second stage
+		abortDur := sleepPerStage + sleepPerStage/2
+		go func() {
+			<-time.After(abortDur)
+			cancel(errAborted)
+		}()
+		go send(in, data)
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range ExecutePipelineContext(ctx, in, stages...) {
+			result = append(result, s.(string))
+		}
+		elapsed := time.Since(start)
+		wg.Wait()
+
+		require.Len(t, result, 0)
+		require.Less(t, int64(elapsed), int64(abortDur)+int64(fault))
+		requireCauses(t, causes, errAborted)
+	})
+
+	t.Run("deadline aborts stages in flight", func(t *testing.T) {
+		// The stopped stages do not read all values.
+		data := []int{1, 2, 3, 4, 5}
+		in := make(Bi, len(data))
+
+		abortDur := sleepPerStage + sleepPerStage/2
+		ctx, cancel := context.WithTimeout(context.Background(), abortDur)
+		defer cancel()
+		go send(in, data)
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range ExecutePipelineContext(ctx, in, stages...) {
+			result = append(result, s.(string))
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+
+		require.Len(t, result, 0)
+		// All stages are stopped without waiting for their sleep.
+		require.Less(t, int64(elapsed), int64(abortDur)+int64(fault))
+		requireCauses(t, causes, context.DeadlineExceeded)
+	})
+}
+
+// requireCauses checks that at least one stage has been stopped in flight with the cause.
+func requireCauses(t *testing.T, causes chan error, cause error) {
+	t.Helper()
+
+	require.NotEmpty(t, causes)
+	for len(causes) > 0 {
+		require.ErrorIs(t, <-causes, cause)
+	}
+}
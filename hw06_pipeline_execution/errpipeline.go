@@ -0,0 +1,179 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrorStage processes one value and may fail.
+type ErrorStage func(v interface{}) (interface{}, error)
+
+// ErrorPolicy decides what ErrorPipeline does with the failed value.
+type ErrorPolicy int
+
+const (
+	// StopOnError stops the whole pipeline on the first error.
+	StopOnError ErrorPolicy = iota
+	// SkipOnError drops the failed value and goes on.
+	SkipOnError
+	// DeadLetterOnError drops the failed value and sends its error to DeadLetter.
+	DeadLetterOnError
+)
+
+// StageError is the error of the stage with the given index for the value.
+type StageError struct {
+	Stage int
+	Value interface{}
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %d: value %v: %s", e.Stage, e.Value, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorPipeline is the running pipeline of ErrorStage. Like in ExecutePipeline, the stages
+// are stopped by done. Every stage runs in its own goroutine.
+type ErrorPipeline struct {
+	policy     ErrorPolicy
+	out        Out
+	deadLetter chan *StageError
+
+	// stop is closed when done is closed or the pipeline is stopped by the error.
+	stop     Bi
+	stopOnce sync.Once
+	finished chan struct{}
+
+	m    sync.Mutex
+	errs []error
+}
+
+// ExecuteErrorPipeline starts the stages with the policy. With DeadLetterOnError
+// DeadLetter must be read, otherwise the pipeline is blocked on the first error.
+func ExecuteErrorPipeline(in, done In, policy ErrorPolicy, stages ...ErrorStage) *ErrorPipeline {
+	p := &ErrorPipeline{
+		policy:     policy,
+		out:        in,
+		deadLetter: make(chan *StageError),
+		stop:       make(Bi),
+		finished:   make(chan struct{}),
+	}
+	if len(stages) == 0 || in == nil {
+		close(p.deadLetter)
+		close(p.finished)
+		return p
+	}
+
+	wg := sync.WaitGroup{}
+	for i, stage := range stages {
+		wg.Add(1)
+		p.out = executor(p.stop, p.run(i, stage, p.out, wg.Done))
+	}
+
+	go func() {
+		select {
+		case <-done:
+			p.stopAll()
+		case <-p.finished:
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(p.deadLetter)
+		close(p.finished)
+	}()
+	return p
+}
+
+func (p *ErrorPipeline) Out() Out {
+	return p.out
+}
+
+// DeadLetter returns the errors of the dropped values with DeadLetterOnError.
+// The channel is closed when all stages are finished.
+func (p *ErrorPipeline) DeadLetter() <-chan *StageError {
+	return p.deadLetter
+}
+
+// Wait waits until all stages are finished and returns the first error. The errors
+// which happen after the pipeline is stopped are not recorded. Out must be read
+// until it is closed, otherwise the last stage is never finished.
+func (p *ErrorPipeline) Wait() error {
+	<-p.finished
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs[0]
+}
+
+// WaitAll works like Wait, but returns all errors joined in the order they happened.
+func (p *ErrorPipeline) WaitAll() error {
+	<-p.finished
+	return errors.Join(p.errs...)
+}
+
+func (p *ErrorPipeline) run(index int, stage ErrorStage, in In, finish func()) Out {
+	out := make(Bi)
+	go func() {
+		defer func() {
+			close(out)
+			finish()
+		}()
+		for v := range in {
+			// The stopped pipeline only drains the input.
+			if p.stopped() {
+				continue
+			}
+			res, err := stage(v)
+			if err != nil {
+				p.fail(&StageError{Stage: index, Value: v, Err: err})
+				continue
+			}
+			out <- res
+		}
+	}()
+	return out
+}
+
+// fail records the error unless the pipeline is already stopped.
+func (p *ErrorPipeline) fail(err *StageError) {
+	p.m.Lock()
+	if p.stopped() {
+		p.m.Unlock()
+		return
+	}
+	p.errs = append(p.errs, err)
+	if p.policy == StopOnError {
+		p.stopAll()
+	}
+	p.m.Unlock()
+
+	switch p.policy {
+	case DeadLetterOnError:
+		select {
+		case <-p.stop:
+		case p.deadLetter <- err:
+		}
+	case StopOnError, SkipOnError:
+	}
+}
+
+func (p *ErrorPipeline) stopped() bool {
+	select {
+	case <-p.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *ErrorPipeline) stopAll() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
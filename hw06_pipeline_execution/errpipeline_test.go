@@ -0,0 +1,161 @@
+package pipeline
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errOdd = errors.New("odd value")
+
+func TestErrorPipeline(t *testing.T) {
+	// Stage generator
+	g := func(f func(v interface{}) (interface{}, error)) ErrorStage {
+		return func(v interface{}) (interface{}, error) {
+			time.Sleep(sleepPerStage / 10)
+			return f(v)
+		}
+	}
+
+	stages := []ErrorStage{
+		g(func(v interface{}) (interface{}, error) { return strconv.Atoi(v.(string)) }),
+		g(func(v interface{}) (interface{}, error) {
+			if v.(int)%2 != 0 {
+				return nil, errOdd
+			}
+			return v, nil
+		}),
+		g(func(v interface{}) (interface{}, error) { return v.(int) * 10, nil }),
+	}
+
+	send := func(data ...string) Bi {
+		in := make(Bi)
+		go func() {
+			for _, v := range data {
+				in <- v
+			}
+			close(in)
+		}()
+		return in
+	}
+
+	collect := func(p *ErrorPipeline) []int {
+		result := make([]int, 0, 10)
+		for v := range p.Out() {
+			result = append(result, v.(int))
+		}
+		return result
+	}
+
+	t.Run("empty stages", func(t *testing.T) {
+		p := ExecuteErrorPipeline(send("1"), nil, StopOnError)
+
+		result := make([]string, 0, 1)
+		for v := range p.Out() {
+			result = append(result, v.(string))
+		}
+
+		require.Equal(t, []string{"1"}, result)
+		require.NoError(t, p.Wait())
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		p := ExecuteErrorPipeline(send("2", "4", "x", "6", "8", "10", "12"), nil, StopOnError, stages...)
+
+		result := collect(p)
+		err := p.Wait()
+
+		require.Subset(t, []int{20, 40}, result)
+		var stageErr *StageError
+		require.ErrorAs(t, err, &stageErr)
+		require.Equal(t, 0, stageErr.Stage)
+		require.Equal(t, "x", stageErr.Value)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+		require.ErrorIs(t, p.WaitAll(), stageErr)
+	})
+
+	t.Run("stages are not called after stop", func(t *testing.T) {
+		data := make([]string, 0, 100)
+		for i := 0; i < 100; i++ {
+			data = append(data, strconv.Itoa(i))
+		}
+
+		// Every value fails, so only the values taken before the stop may be processed.
+		var calls int32
+		failing := func(v interface{}) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errOdd
+		}
+		p := ExecuteErrorPipeline(send(data...), nil, StopOnError, failing)
+
+		require.Empty(t, collect(p))
+		require.ErrorIs(t, p.Wait(), errOdd)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		require.Len(t, p.WaitAll().(interface{ Unwrap() []error }).Unwrap(), 1)
+	})
+
+	t.Run("skip on error", func(t *testing.T) {
+		p := ExecuteErrorPipeline(send("1", "2", "x", "3", "4"), nil, SkipOnError, stages...)
+
+		result := collect(p)
+
+		require.Equal(t, []int{20, 40}, result)
+		require.ErrorIs(t, p.Wait(), errOdd)
+		err := p.WaitAll()
+		require.ErrorIs(t, err, errOdd)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+		require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		p := ExecuteErrorPipeline(send("1", "2", "x", "3", "4"), nil, DeadLetterOnError, stages...)
+
+		var deadLetter []interface{}
+		collected := make(chan struct{})
+		go func() {
+			defer close(collected)
+			for err := range p.DeadLetter() {
+				deadLetter = append(deadLetter, err.Value)
+			}
+		}()
+
+		result := collect(p)
+		<-collected
+
+		require.Equal(t, []int{20, 40}, result)
+		require.ElementsMatch(t, []interface{}{1, "x", 3}, deadLetter)
+		require.Error(t, p.WaitAll())
+	})
+
+	t.Run("done case", func(t *testing.T) {
+		done := make(Bi)
+		slow := func(v interface{}) (interface{}, error) {
+			time.Sleep(sleepPerStage)
+			return v, nil
+		}
+
+		// Abort after 200ms
+		abortDur := sleepPerStage * 2
+		go func() {
+			<-time.After(abortDur)
+			close(done)
+		}()
+
+		p := ExecuteErrorPipeline(send("1", "2", "3", "4", "5"), done, SkipOnError, slow, slow, slow, slow)
+
+		result := make([]interface{}, 0, 10)
+		start := time.Now()
+		for v := range p.Out() {
+			result = append(result, v)
+		}
+		elapsed := time.Since(start)
+
+		require.Len(t, result, 0)
+		require.Less(t, int64(elapsed), int64(abortDur)+int64(fault))
+		require.NoError(t, p.Wait())
+	})
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import "sync"
+
+// Parallel runs n copies of the stage reading the same input and merges their outputs.
+// The order of values is not kept. n less than 1 means 1.
+func Parallel(n int, stage Stage) Stage {
+	return Stage(TypedParallel(n, TypedStage[interface{}, interface{}](stage)))
+}
+
+// ParallelOrdered works like Parallel, but keeps the order of values. The input values are
+// dealt to the copies of the stage in turn, so the stage must emit exactly one value for each
+// input value before taking the next one.
+func ParallelOrdered(n int, stage Stage) Stage {
+	return Stage(TypedParallelOrdered(n, TypedStage[interface{}, interface{}](stage)))
+}
+
+func TypedParallel[I, O any](n int, stage TypedStage[I, O]) TypedStage[I, O] {
+	n = max(n, 1)
+	return func(in <-chan I) <-chan O {
+		outs := make([]<-chan O, 0, n)
+		for i := 0; i < n; i++ {
+			outs = append(outs, stage(in))
+		}
+		return merge(outs)
+	}
+}
+
+func TypedParallelOrdered[I, O any](n int, stage TypedStage[I, O]) TypedStage[I, O] {
+	n = max(n, 1)
+	return func(in <-chan I) <-chan O {
+		ins := make([]chan I, 0, n)
+		outs := make([]<-chan O, 0, n)
+		for i := 0; i < n; i++ {
+			ins = append(ins, make(chan I))
+			outs = append(outs, stage(ins[i]))
+		}
+
+		go func() {
+			defer func() {
+				for _, stageIn := range ins {
+					close(stageIn)
+				}
+			}()
+			i := 0
+			for v := range in {
+				ins[i] <- v
+				i = (i + 1) % n
+			}
+		}()
+
+		out := make(chan O)
+		go func() {
+			defer func() {
+				close(out)
+				for _, stageOut := range outs {
+					for range stageOut {
+						_ = stageOut
+					}
+				}
+			}()
+			for i := 0; ; i = (i + 1) % n {
+				v, ok := <-outs[i]
+				if !ok {
+					return
+				}
+				out <- v
+			}
+		}()
+		return out
+	}
+}
+
+func merge[T any](outs []<-chan T) <-chan T {
+	out := make(chan T)
+	wg := sync.WaitGroup{}
+	for _, stageOut := range outs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range stageOut {
+				out <- v
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallel(t *testing.T) {
+	wg := sync.WaitGroup{}
+	// Stage generator, the stage sleeps for the given time per value.
+	g := func(sleep func() time.Duration, f func(v interface{}) interface{}) Stage {
+		return func(in In) Out {
+			out := make(Bi)
+			wg.Add(1)
+			go func() {
+				defer func() {
+					wg.Done()
+					close(out)
+				}()
+				for v := range in {
+					time.Sleep(sleep())
+					out <- f(v)
+				}
+			}()
+			return out
+		}
+	}
+	constant := func() time.Duration {
+		return sleepPerStage
+	}
+	random := func() time.Duration {
+		return time.Duration(rand.Int63n(int64(sleepPerStage / 5)))
+	}
+	workers := 4
+
+	send := func(in Bi, data []int) {
+		for _, v := range data {
+			in <- v
+		}
+		close(in)
+	}
+
+	t.Run("unordered", func(t *testing.T) {
+		in := make(Bi)
+		data := []int{1, 2, 3, 4, 5, 6, 7, 8}
+		go send(in, data)
+
+		stages := []Stage{
+			Parallel(workers, g(constant, func(v interface{}) interface{} { return v.(int) * 2 })),
+			g(func() time.Duration { return 0 }, func(v interface{}) interface{} { return strconv.Itoa(v.(int)) }),
+		}
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range ExecutePipeline(in, nil, stages...) {
+			result = append(result, s.(string))
+		}
+		elapsed := time.Since(start)
+		wg.Wait()
+
+		require.ElementsMatch(t, []string{"2", "4", "6", "8", "10", "12", "14", "16"}, result)
+		// ~0.2s for processing 8 values by 4 workers (100ms every)
+		require.Less(t, int64(elapsed), int64(sleepPerStage)*int64(len(data)/workers)+int64(fault))
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		in := make(Bi)
+		data := make([]int, 100)
+		for i := range data {
+			data[i] = i
+		}
+		go send(in, data)
+
+		stage := ParallelOrdered(workers, g(random, func(v interface{}) interface{} { return v.(int) * 2 }))
+
+		result := make([]int, 0, len(data))
+		for v := range ExecutePipeline(in, nil, stage) {
+			result = append(result, v.(int))
+		}
+		wg.Wait()
+
+		require.Len(t, result, len(data))
+		for i, v := range result {
+			require.Equal(t, i*2, v)
+		}
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		in := make(chan int)
+		go func() {
+			for i := 0; i < 10; i++ {
+				in <- i
+			}
+			close(in)
+		}()
+
+		p := Then(
+			NewTypedPipeline(TypedParallelOrdered(workers, typedStage(&wg, func(v int) int { return v + 1 }))),
+			TypedParallel(workers, typedStage(&wg, strconv.Itoa)),
+		)
+
+		result := make([]string, 0, 10)
+		for s := range p.Execute(in, nil) {
+			result = append(result, s)
+		}
+		wg.Wait()
+
+		require.ElementsMatch(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, result)
+	})
+
+	for _, mode := range []struct {
+		name     string
+		parallel func(n int, stage Stage) Stage
+	}{
+		{name: "unordered", parallel: Parallel},
+		{name: "ordered", parallel: ParallelOrdered},
+	} {
+		t.Run(mode.name+" done case", func(t *testing.T) {
+			in := make(Bi)
+			done := make(Bi)
+			data := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+			// Abort after 200ms
+			abortDur := sleepPerStage * 2
+			go func() {
+				<-time.After(abortDur)
+				close(done)
+			}()
+			go send(in, data)
+
+			stages := []Stage{
+				mode.parallel(2, g(constant, func(v interface{}) interface{} { return v })),
+				mode.parallel(2, g(constant, func(v interface{}) interface{} { return v })),
+				mode.parallel(2, g(constant, func(v interface{}) interface{} { return v })),
+			}
+
+			result := make([]interface{}, 0, 10)
+			start := time.Now()
+			for v := range ExecutePipeline(in, done, stages...) {
+				result = append(result, v)
+			}
+			elapsed := time.Since(start)
+			wg.Wait()
+
+			require.Len(t, result, 0)
+			require.Less(t, int64(elapsed), int64(abortDur)+int64(fault))
+		})
+	}
+}
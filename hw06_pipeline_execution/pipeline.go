@@ -25,7 +25,13 @@ func ExecutePipeline(in In, done In, stages ...Stage) Out {
 }
 
 func executor(done, stageOut In) Out {
-	out := make(Bi)
+	return typedExecutor(done, stageOut)
+}
+
+// typedExecutor forwards the stage output until done is closed. Then it drains the stage
+// output so the stage goroutine is not blocked forever.
+func typedExecutor[T, D any](done <-chan D, stageOut <-chan T) <-chan T {
+	out := make(chan T)
 
 	go func() {
 		defer func() {
@@ -0,0 +1,263 @@
+package pipeline
+
+import "time"
+
+// Clock is the source of time for the time based stages.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Stages builds the common stages for ExecutePipeline. The stages stop when done is closed,
+// even if they wait for time, and then drain their input like executor does.
+type Stages struct {
+	done  In
+	clock Clock
+}
+
+// NewStages returns the stages stopped by done. nil clock means the real time.
+func NewStages(done In, clock Clock) Stages {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return Stages{done: done, clock: clock}
+}
+
+// Map replaces every value with the result of f.
+func (s Stages) Map(f func(v interface{}) interface{}) Stage {
+	return s.stage(0, func(in In, out Bi) {
+		for v := range in {
+			if !s.send(out, f(v)) {
+				return
+			}
+		}
+	})
+}
+
+// Filter keeps only the values for which keep returns true.
+func (s Stages) Filter(keep func(v interface{}) bool) Stage {
+	return s.stage(0, func(in In, out Bi) {
+		for v := range in {
+			if keep(v) && !s.send(out, v) {
+				return
+			}
+		}
+	})
+}
+
+// Dedupe drops the values with the key seen before. nil key means the value itself,
+// which must be comparable then. All keys are kept until the stage is finished.
+func (s Stages) Dedupe(key func(v interface{}) interface{}) Stage {
+	if key == nil {
+		key = func(v interface{}) interface{} {
+			return v
+		}
+	}
+	return s.stage(0, func(in In, out Bi) {
+		seen := map[interface{}]struct{}{}
+		for v := range in {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !s.send(out, v) {
+				return
+			}
+		}
+	})
+}
+
+// Buffer lets the previous stage go on until capacity values are waiting for the next one.
+func (s Stages) Buffer(capacity int) Stage {
+	return s.stage(capacity, func(in In, out Bi) {
+		for v := range in {
+			if !s.send(out, v) {
+				return
+			}
+		}
+	})
+}
+
+// Tee copies every value to n extra outputs. The value goes on after all outputs have received
+// it, so they must be read. The outputs are closed when the stage is finished. The stage may be
+// used in one pipeline only.
+func (s Stages) Tee(n int) (Stage, []Out) {
+	tees := make([]Bi, 0, n)
+	outs := make([]Out, 0, n)
+	for i := 0; i < n; i++ {
+		tees = append(tees, make(Bi))
+		outs = append(outs, tees[i])
+	}
+
+	stage := s.stage(0, func(in In, out Bi) {
+		defer func() {
+			for _, tee := range tees {
+				close(tee)
+			}
+		}()
+		for v := range in {
+			for _, tee := range tees {
+				if !s.send(tee, v) {
+					return
+				}
+			}
+			if !s.send(out, v) {
+				return
+			}
+		}
+	})
+	return stage, outs
+}
+
+// Batch groups the values into []interface{} of size values. The incomplete batch
+// is sent when maxWait passes after its first value or the input is closed. size less
+// than 1 means 1.
+func (s Stages) Batch(size int, maxWait time.Duration) Stage {
+	size = max(size, 1)
+	return s.stage(0, func(in In, out Bi) {
+		var batch []interface{}
+		var timeout <-chan time.Time
+		for {
+			select {
+			case <-s.done:
+				return
+			case v, ok := <-in:
+				if !ok {
+					s.flush(out, batch)
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 {
+					timeout = s.clock.After(maxWait)
+				}
+				if len(batch) < size {
+					continue
+				}
+			case <-timeout:
+			}
+
+			if !s.flush(out, batch) {
+				return
+			}
+			batch = nil
+			timeout = nil
+		}
+	})
+}
+
+// TumblingWindow groups the values received during every period into []interface{}.
+// Empty windows are not sent.
+func (s Stages) TumblingWindow(period time.Duration) Stage {
+	return s.stage(0, func(in In, out Bi) {
+		var window []interface{}
+		tick := s.clock.After(period)
+		for {
+			select {
+			case <-s.done:
+				return
+			case v, ok := <-in:
+				if !ok {
+					s.flush(out, window)
+					return
+				}
+				window = append(window, v)
+			case <-tick:
+				if !s.flush(out, window) {
+					return
+				}
+				window = nil
+				tick = s.clock.After(period)
+			}
+		}
+	})
+}
+
+// SlidingWindow sends the last size values as []interface{} after every step values.
+// The first window is sent when size values are received. size and step less than 1 mean 1.
+func (s Stages) SlidingWindow(size, step int) Stage {
+	size, step = max(size, 1), max(step, 1)
+	return s.stage(0, func(in In, out Bi) {
+		window := make([]interface{}, 0, size)
+		var received int
+		for v := range in {
+			received++
+			if len(window) == size {
+				window = window[1:]
+			}
+			window = append(window, v)
+			if received < size || (received-size)%step != 0 {
+				continue
+			}
+			if !s.send(out, append([]interface{}(nil), window...)) {
+				return
+			}
+		}
+	})
+}
+
+// Throttle sends the values not more often than once per interval.
+func (s Stages) Throttle(interval time.Duration) Stage {
+	return s.stage(0, func(in In, out Bi) {
+		var next time.Time
+		for v := range in {
+			if delay := next.Sub(s.clock.Now()); delay > 0 {
+				select {
+				case <-s.done:
+					return
+				case <-s.clock.After(delay):
+				}
+			}
+			if !s.send(out, v) {
+				return
+			}
+			next = s.clock.Now().Add(interval)
+		}
+	})
+}
+
+// stage starts run in the goroutine. When run returns, the output is closed and the input
+// is drained.
+func (s Stages) stage(capacity int, run func(in In, out Bi)) Stage {
+	return func(in In) Out {
+		out := make(Bi, capacity)
+		go func() {
+			defer func() {
+				close(out)
+				for range in {
+					_ = in
+				}
+			}()
+			run(in, out)
+		}()
+		return out
+	}
+}
+
+// send reports false if done is closed before the value is sent.
+func (s Stages) send(out Bi, v interface{}) bool {
+	select {
+	case <-s.done:
+		return false
+	case out <- v:
+		return true
+	}
+}
+
+// flush sends the values if there are any.
+func (s Stages) flush(out Bi, values []interface{}) bool {
+	if len(values) == 0 {
+		return true
+	}
+	return s.send(out, values)
+}
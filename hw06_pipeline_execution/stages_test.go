@@ -0,0 +1,249 @@
+package pipeline
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// fakeClock moves only by Advance. Waiters lets the tests see that a stage waits for the time.
+type fakeClock struct {
+	m   sync.Mutex
+	now time.Time
+	// timers holds the time every pending timer fires at.
+	timers map[chan time.Time]time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{timers: map[chan time.Time]time.Time{}}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.timers[ch] = c.now.Add(d)
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.now = c.now.Add(d)
+	for ch, at := range c.timers {
+		if !at.After(c.now) {
+			ch <- c.now
+			delete(c.timers, ch)
+		}
+	}
+}
+
+func (c *fakeClock) Waiters() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return len(c.timers)
+}
+
+func TestStages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	send := func(data ...interface{}) Bi {
+		in := make(Bi)
+		go func() {
+			for _, v := range data {
+				in <- v
+			}
+			close(in)
+		}()
+		return in
+	}
+
+	collect := func(out Out) []interface{} {
+		result := make([]interface{}, 0, 10)
+		for v := range out {
+			result = append(result, v)
+		}
+		return result
+	}
+
+	// receive waits for the next value with the real time limit.
+	receive := func(t *testing.T, out Out) interface{} {
+		t.Helper()
+		select {
+		case v := <-out:
+			return v
+		case <-time.After(time.Second):
+			require.Fail(t, "no value received")
+			return nil
+		}
+	}
+
+	s := NewStages(nil, nil)
+
+	t.Run("map filter dedupe", func(t *testing.T) {
+		out := ExecutePipeline(send(1, 2, 3, 2, 4, 1, 5, 6), nil,
+			s.Filter(func(v interface{}) bool { return v.(int)%2 == 0 }),
+			s.Dedupe(nil),
+			s.Map(func(v interface{}) interface{} { return v.(int) * 10 }),
+		)
+
+		require.Equal(t, []interface{}{20, 40, 60}, collect(out))
+	})
+
+	t.Run("dedupe by key", func(t *testing.T) {
+		out := ExecutePipeline(send("a", "bb", "cc", "d", "eee"), nil,
+			s.Dedupe(func(v interface{}) interface{} { return len(v.(string)) }))
+
+		require.Equal(t, []interface{}{"a", "bb", "eee"}, collect(out))
+	})
+
+	t.Run("buffer", func(t *testing.T) {
+		in := make(Bi)
+		var sent int32
+		go func() {
+			defer close(in)
+			for i := 0; i < 5; i++ {
+				in <- i
+				atomic.AddInt32(&sent, 1)
+			}
+		}()
+
+		out := ExecutePipeline(in, nil, s.Buffer(3))
+
+		// The values wait in the buffer while the output is not read.
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&sent) >= 3
+		}, time.Second, time.Millisecond)
+		require.Equal(t, []interface{}{0, 1, 2, 3, 4}, collect(out))
+	})
+
+	t.Run("tee", func(t *testing.T) {
+		stage, tees := s.Tee(2)
+		out := ExecutePipeline(send(1, 2, 3), nil, stage)
+
+		results := make([][]interface{}, len(tees))
+		wg := sync.WaitGroup{}
+		for i, tee := range tees {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = collect(tee)
+			}()
+		}
+
+		require.Equal(t, []interface{}{1, 2, 3}, collect(out))
+		wg.Wait()
+		for _, result := range results {
+			require.Equal(t, []interface{}{1, 2, 3}, result)
+		}
+	})
+
+	t.Run("sliding window", func(t *testing.T) {
+		out := ExecutePipeline(send(1, 2, 3, 4, 5, 6, 7), nil, s.SlidingWindow(3, 2))
+
+		require.Equal(t, []interface{}{
+			[]interface{}{1, 2, 3},
+			[]interface{}{3, 4, 5},
+			[]interface{}{5, 6, 7},
+		}, collect(out))
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		clock := newFakeClock()
+		in := make(Bi)
+		out := ExecutePipeline(in, nil, NewStages(nil, clock).Batch(2, time.Second))
+
+		for i := 1; i <= 3; i++ {
+			in <- i
+		}
+		require.Equal(t, []interface{}{1, 2}, receive(t, out))
+
+		// The batch of one value waits for the time.
+		require.Eventually(t, func() bool {
+			return clock.Waiters() == 2
+		}, time.Second, time.Millisecond)
+		clock.Advance(time.Second)
+		require.Equal(t, []interface{}{3}, receive(t, out))
+
+		in <- 4
+		close(in)
+		require.Equal(t, []interface{}{[]interface{}{4}}, collect(out))
+	})
+
+	t.Run("tumbling window", func(t *testing.T) {
+		clock := newFakeClock()
+		in := make(Bi)
+		out := ExecutePipeline(in, nil, NewStages(nil, clock).TumblingWindow(time.Second))
+
+		in <- 1
+		in <- 2
+		clock.Advance(time.Second)
+		require.Equal(t, []interface{}{1, 2}, receive(t, out))
+
+		// The empty window is not sent.
+		require.Eventually(t, func() bool {
+			return clock.Waiters() == 1
+		}, time.Second, time.Millisecond)
+		clock.Advance(time.Second)
+		in <- 3
+		close(in)
+		require.Equal(t, []interface{}{[]interface{}{3}}, collect(out))
+	})
+
+	t.Run("throttle", func(t *testing.T) {
+		clock := newFakeClock()
+		out := ExecutePipeline(send(1, 2, 3), nil, NewStages(nil, clock).Throttle(time.Second))
+
+		require.Equal(t, 1, receive(t, out))
+		for i := 2; i <= 3; i++ {
+			require.Eventually(t, func() bool {
+				return clock.Waiters() == 1
+			}, time.Second, time.Millisecond)
+			clock.Advance(time.Second)
+			require.Equal(t, i, receive(t, out))
+		}
+		require.Empty(t, collect(out))
+	})
+
+	t.Run("done case", func(t *testing.T) {
+		clock := newFakeClock()
+		done := make(Bi)
+		s := NewStages(done, clock)
+		tee, tees := s.Tee(1)
+
+		// The stages wait for the time which never comes.
+		in := make(Bi)
+		out := ExecutePipeline(in, done,
+			s.Map(func(v interface{}) interface{} { return v }),
+			s.Buffer(1),
+			tee,
+			s.Batch(10, time.Second),
+			s.Throttle(time.Second),
+			s.TumblingWindow(time.Second),
+		)
+
+		in <- 1
+		require.Equal(t, 1, receive(t, tees[0]))
+		in <- 2
+		require.Equal(t, 2, receive(t, tees[0]))
+
+		close(done)
+		require.Empty(t, collect(out))
+		require.Empty(t, collect(tees[0]))
+		close(in)
+	})
+}
@@ -0,0 +1,29 @@
+package pipeline
+
+// TypedStage is the Stage with the values of type I in and of type O out.
+type TypedStage[I, O any] func(in <-chan I) (out <-chan O)
+
+// TypedPipeline is the chain of typed stages checked at compile time. Like in ExecutePipeline,
+// every stage output is stopped by done and drained.
+type TypedPipeline[I, O any] func(in <-chan I, done In) <-chan O
+
+func NewTypedPipeline[I, O any](stage TypedStage[I, O]) TypedPipeline[I, O] {
+	return func(in <-chan I, done In) <-chan O {
+		return typedExecutor(done, stage(in))
+	}
+}
+
+// Then appends the stage to the pipeline.
+func Then[A, B, C any](p TypedPipeline[A, B], stage TypedStage[B, C]) TypedPipeline[A, C] {
+	return func(in <-chan A, done In) <-chan C {
+		return typedExecutor(done, stage(p(in, done)))
+	}
+}
+
+// Execute starts the pipeline. As in ExecutePipeline, nil in returns nil.
+func (p TypedPipeline[I, O]) Execute(in <-chan I, done In) <-chan O {
+	if in == nil {
+		return nil
+	}
+	return p(in, done)
+}
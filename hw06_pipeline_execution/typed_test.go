@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// typedStage is the stage generator like in TestPipeline. wg tracks the stage goroutines if not nil.
+func typedStage[I, O any](wg *sync.WaitGroup, f func(v I) O) TypedStage[I, O] {
+	return func(in <-chan I) <-chan O {
+		out := make(chan O)
+		if wg != nil {
+			wg.Add(1)
+		}
+		go func() {
+			defer func() {
+				if wg != nil {
+					wg.Done()
+				}
+				close(out)
+			}()
+			for v := range in {
+				time.Sleep(sleepPerStage)
+				out <- f(v)
+			}
+		}()
+		return out
+	}
+}
+
+func typedStages(wg *sync.WaitGroup) TypedPipeline[int, string] {
+	p := NewTypedPipeline(typedStage(wg, func(v int) int { return v }))
+	p = Then(p, typedStage(wg, func(v int) int { return v * 2 }))
+	p = Then(p, typedStage(wg, func(v int) int { return v + 100 }))
+	return Then(p, typedStage(wg, strconv.Itoa))
+}
+
+func TestTypedPipeline(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5}
+	send := func(in chan<- int) {
+		for _, v := range data {
+			in <- v
+		}
+		close(in)
+	}
+
+	t.Run("nil in channel", func(t *testing.T) {
+		require.Nil(t, typedStages(nil).Execute(nil, nil))
+	})
+
+	t.Run("closed in channel", func(t *testing.T) {
+		in := make(chan int)
+		close(in)
+
+		result := make([]string, 0, 5)
+		for s := range typedStages(nil).Execute(in, make(Bi)) {
+			result = append(result, s)
+		}
+
+		require.Len(t, result, 0)
+	})
+
+	t.Run("simple case", func(t *testing.T) {
+		in := make(chan int)
+		go send(in)
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range typedStages(nil).Execute(in, nil) {
+			result = append(result, s)
+		}
+		elapsed := time.Since(start)
+
+		require.Equal(t, []string{"102", "104", "106", "108", "110"}, result)
+		require.Less(t,
+			int64(elapsed),
+			// ~0.8s for processing 5 values in 4 stages (100ms every) concurrently
+			int64(sleepPerStage)*int64(4+len(data)-1)+int64(fault))
+	})
+
+	t.Run("done case", func(t *testing.T) {
+		in := make(chan int)
+		done := make(Bi)
+		wg := sync.WaitGroup{}
+
+		// Abort after 200ms
+		abortDur := sleepPerStage * 2
+		go func() {
+			<-time.After(abortDur)
+			close(done)
+		}()
+		go send(in)
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range typedStages(&wg).Execute(in, done) {
+			result = append(result, s)
+		}
+		elapsed := time.Since(start)
+		wg.Wait()
+
+		require.Len(t, result, 0)
+		require.Less(t, int64(elapsed), int64(abortDur)+int64(fault))
+	})
+}